@@ -0,0 +1,65 @@
+package brainfuck
+
+import (
+	"math/big"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Arithmetic defines how built-in commands change and check cell values.
+// It allows to use cell types that don't support Go arithmetic operators, i.e. *big.Int.
+type Arithmetic[DataType any] interface {
+
+	// Zero returns a value of an empty cell
+	Zero() DataType
+
+	// Inc returns v+1
+	Inc(v DataType) DataType
+
+	// Dec returns v-1
+	Dec(v DataType) DataType
+
+	// IsZero checks whether the cell is empty
+	IsZero(v DataType) bool
+}
+
+// IntArithmetic implements Arithmetic for integer cells. Values wrap around on overflow.
+type IntArithmetic[DataType constraints.Integer] struct{}
+
+func (IntArithmetic[DataType]) Zero() DataType          { return 0 }
+func (IntArithmetic[DataType]) Inc(v DataType) DataType { return v + 1 }
+func (IntArithmetic[DataType]) Dec(v DataType) DataType { return v - 1 }
+func (IntArithmetic[DataType]) IsZero(v DataType) bool  { return v == 0 }
+
+// BigArithmetic implements Arithmetic for arbitrary-precision *big.Int cells.
+// Values are never changed in place, so cells may share pointers. nil is treated as 0.
+type BigArithmetic struct{}
+
+var (
+	bigZero = big.NewInt(0)
+	bigOne  = big.NewInt(1)
+)
+
+func (BigArithmetic) Zero() *big.Int {
+	return bigZero
+}
+
+func (BigArithmetic) Inc(v *big.Int) *big.Int {
+	if v == nil {
+		v = bigZero
+	}
+
+	return new(big.Int).Add(v, bigOne)
+}
+
+func (BigArithmetic) Dec(v *big.Int) *big.Int {
+	if v == nil {
+		v = bigZero
+	}
+
+	return new(big.Int).Sub(v, bigOne)
+}
+
+func (BigArithmetic) IsZero(v *big.Int) bool {
+	return v == nil || v.Sign() == 0
+}
@@ -0,0 +1,128 @@
+package brainfuck
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// CmdFork is Brainfork command that forks the interpreter
+const CmdFork = CmdType('Y')
+
+// EnableBrainfork enables Brainfork extension: Fork ('Y') command starts a new thread.
+// The current cell of the parent thread is set to zero. The child thread continues after 'Y' with the data pointer
+// moved to the next cell that is set to one. Both threads share the tape, Input and Output.
+//
+// Threads are goroutines, but they are run by a deterministic scheduler:
+// only one thread processes a command at a time, and the next thread is chosen by a PRNG seeded with seed.
+// So there are no data races on the tape, and the order of output is the same for the same seed.
+// Run returns when all threads are over or when any of them fails.
+// All commands are retained in cache, as threads may be at different positions of the program.
+func EnableBrainfork[DataType any](bf *BfInterpreter[DataType], seed int64) *BfInterpreter[DataType] {
+	bf.forks = &forkScheduler[DataType]{
+		seed: seed,
+	}
+
+	bf.RetainCommands()
+
+	return bf.WithCmd(CmdFork, opFork[DataType])
+}
+
+// forkScheduler runs Brainfork threads one command at a time
+type forkScheduler[DataType any] struct {
+	seed int64
+
+	// forked are threads that were forked during the last step and are not started yet
+	forked []*BfInterpreter[DataType]
+}
+
+// thread is a goroutine that runs an interpreter step by step when it gets a turn
+type thread[DataType any] struct {
+	bf   *BfInterpreter[DataType]
+	turn chan struct{}
+	done chan stepResult
+}
+
+type stepResult struct {
+	done bool
+	err  error
+}
+
+// opFork is a handler for Fork ('Y') command
+func opFork[DataType any](bf *BfInterpreter[DataType]) error {
+	if bf.DataPtr >= bf.TapeLen()-1 {
+		return fmt.Errorf("fork moves out of boundary")
+	}
+
+	child := *bf
+	child.loopStack = bf.loopStack.Clone()
+	child.DataPtr++
+	child.CmdPtr++ // the parent's CmdPtr will be incremented after the command
+	child.SetCell(bf.arith.Inc(bf.arith.Zero()))
+
+	bf.SetCell(bf.arith.Zero())
+
+	bf.forks.forked = append(bf.forks.forked, &child)
+
+	return nil
+}
+
+// runThreads runs the interpreter as the main thread and all threads that are forked from it
+func (bf *BfInterpreter[DataType]) runThreads() error {
+	rnd := rand.New(rand.NewSource(bf.forks.seed)) //nolint:gosec
+	bf.forks.forked = nil
+
+	quit := make(chan struct{})
+	defer close(quit)
+
+	threads := []*thread[DataType]{startThread(bf, quit)}
+
+	for len(threads) > 0 {
+		i := rnd.Intn(len(threads))
+		t := threads[i]
+
+		t.turn <- struct{}{}
+		res := <-t.done
+
+		if res.err != nil {
+			return fmt.Errorf("thread failed: %w", res.err)
+		}
+
+		if res.done {
+			threads = append(threads[:i], threads[i+1:]...)
+		}
+
+		for _, child := range bf.forks.forked {
+			threads = append(threads, startThread(child, quit))
+		}
+		bf.forks.forked = nil
+	}
+
+	return nil
+}
+
+func startThread[DataType any](bf *BfInterpreter[DataType], quit <-chan struct{}) *thread[DataType] {
+	t := thread[DataType]{
+		bf:   bf,
+		turn: make(chan struct{}),
+		done: make(chan stepResult),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-quit:
+				return
+			case <-t.turn:
+			}
+
+			done, err := t.bf.step()
+			t.done <- stepResult{done: done, err: err}
+
+			if done || err != nil {
+				return
+			}
+		}
+	}()
+
+	return &t
+}
@@ -0,0 +1,60 @@
+package brainfuck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableBrainfork(t *testing.T) {
+	t.Parallel()
+
+	// The child thread increments and prints cell 3, then prints cell 4.
+	// The parent thread adds 2 to cell 3 and prints it. The output depends on scheduling.
+	const program = `Y[>>+.<<-]>>>++.`
+
+	run := func(seed int64) ([]TestDataType, []TestDataType) {
+		output := writer.BuildSliceWriter[TestDataType]()
+
+		bf := New[TestDataType](5, reader.BuildSliceReader[TestDataType](), output)
+		EnableBrainfork(bf, seed)
+
+		resData, err := bf.Run(strings.NewReader(program))
+		require.NoError(t, err)
+
+		return resData, output.Values()
+	}
+
+	orders := make(map[string]bool)
+
+	for seed := int64(0); seed < 20; seed++ {
+		resData, output := run(seed)
+
+		require.Equal(t, []TestDataType{0, 0, 0, 3, 2}, resData)
+		require.Len(t, output, 3)
+
+		// the same seed gives the same output
+		_, repeated := run(seed)
+		require.Equal(t, output, repeated)
+
+		orders[fmt.Sprint(output)] = true
+	}
+
+	require.Greater(t, len(orders), 1)
+}
+
+func TestEnableBrainfork_Errors(t *testing.T) {
+	t.Parallel()
+
+	bf := New[TestDataType](2, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]())
+	EnableBrainfork(bf, 1)
+
+	// the child of the second fork is out of the tape
+	_, err := bf.Run(strings.NewReader(`Y>Y`))
+	require.Error(t, err)
+}
@@ -7,7 +7,9 @@
 // Here are points that explain an interpreter implementation:
 //
 // 1. Memory data type.
-// We're using generics to define memory data type. This gives user freedom to pick any type that fits the best
+// We're using generics to define memory data type. This gives user freedom to pick any type that fits the best.
+// Any integer type may be used with New, signed or unsigned. Cells of other types (e.g. *big.Int with NewBig)
+// are supported through Arithmetic interface that defines how built-in commands change and check cell values.
 //
 // 2. Commands type.
 // CmdType is byte. While we're not planning to give user a possibility to extend the language too much byte type is enough.
@@ -34,26 +36,45 @@
 //
 // 6. Commands overloading
 // Custom commands handlers have access to public members – Data, DataPtr, CmdPtr, Input and Output.
+// Cell() and SetCell() should be preferred to Data to support handlers that work with any Tape.
+// New control-flow commands (loops, conditions) are built with control frames and forward scanning (see control.go).
 // So custom command handler can read and write data to memory, setup where other commands will read/write,
 // manage what the next command will be and read/write data from/to user.
 //
+// 7. End of input
+// Readers report the end of input with io.EOF. By default In command fails in this case,
+// but it may be configured with WithEOFPolicy to set the cell to 0 or -1 or to leave it unchanged.
+// It allows to chain interpreters with reader.ChanReader and writer.ChanWriter where closing the channel ends the input.
+//
+// 8. Memory
+// By default memory is a slice (Data) that is allocated by New. For huge address spaces it may be replaced with
+// any Tape implementation with NewWithTape or WithTape, i.e. SparseTape that allocates pages only when cells are changed.
+// A run starts from the first cell unless the tape stores the pointer (PointerTape), i.e. MmapTape.
+//
 package brainfuck
 
 import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"sort"
+	"strings"
 
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
 	"github.com/yurii-vyrovyi/brainfuck/stack"
 
 	"golang.org/x/exp/constraints"
 )
 
-type BfInterpreter[DataType constraints.Signed] struct {
+type BfInterpreter[DataType any] struct {
 
-	// Data is a memory for brainfuck algorithm
+	// Data is a memory for brainfuck algorithm. It's not used when Tape is set.
 	Data []DataType
 
+	// Tape is an alternative memory implementation, i.e. SparseTape. Data is used when it's nil.
+	Tape Tape[DataType]
+
 	// CmdPtr is a current command pointer
 	CmdPtr CmdPtrType
 
@@ -69,14 +90,50 @@ type BfInterpreter[DataType constraints.Signed] struct {
 	// loopStack stores the addresses of a loops beginnings
 	loopStack *stack.Stack[CmdPtrType]
 
-	// cmdCache is a commands cache that is used while interpreter runs loops
-	cmdCache CmdCache
+	// src is a stream of the code that is being run with commands cache that is used while interpreter runs loops
+	src *cmdSource
 
 	// opMap stores correspondence between commands and handlers
 	opMap map[CmdType]OpFunc[DataType]
 
+	// middlewares wrap every handler in the order they were added
+	middlewares []Middleware[DataType]
+
+	// handlers caches opMap handlers wrapped with middlewares. It's rebuilt when it's nil.
+	handlers map[CmdType]OpFunc[DataType]
+
+	// cmd is the command that is being processed
+	cmd CmdType
+
 	// currentLoopEnd stores the command address of the end of the current loop
 	currentLoopEnd CmdPtrType
+
+	// eofPolicy defines In command behaviour when Input reaches the end
+	eofPolicy EOFPolicy
+
+	// promptFormatter builds a prompt text for In command. Prompts are disabled when it's nil.
+	promptFormatter PromptFormatter[DataType]
+
+	// arith is used by built-in commands to change and check cell values
+	arith Arithmetic[DataType]
+
+	// retainCmds keeps all read commands cached, so handlers may jump anywhere backwards
+	retainCmds bool
+
+	// resetHooks are called when interpreter state is reset. They let extensions clear their state.
+	resetHooks []func()
+
+	// finishHooks are called when Run ends. They let extensions restore the state that Run returns.
+	finishHooks []func()
+
+	// forks is a scheduler of Brainfork threads. It's nil if Brainfork is disabled.
+	forks *forkScheduler[DataType]
+
+	// sourceMap translates CmdPtr to positions in the original sources. It's nil if there's no source map.
+	sourceMap SourceMap
+
+	// inputFromCommands builds Input from the rest of commands after Stop ('!') command. It's nil if '!' is disabled.
+	inputFromCommands func(r io.Reader) InputReader[DataType]
 }
 
 type (
@@ -93,29 +150,92 @@ type (
 	CmdCache map[CmdPtrType]CmdType
 
 	// OpFunc is type for brainfuck commands handlers.
-	OpFunc[DataType constraints.Signed] func(bf *BfInterpreter[DataType]) error
+	OpFunc[DataType any] func(bf *BfInterpreter[DataType]) error
+
+	// Middleware wraps a command handler with cross-cutting behaviour (logging, metrics, permission checks etc.).
+	// It should call next to process the command.
+	Middleware[DataType any] func(next OpFunc[DataType]) OpFunc[DataType]
+
+	// PromptFormatter builds a prompt text that is passed to InputReader with a hint.
+	// It gets the position of In command, the index of the cell and its current value.
+	PromptFormatter[DataType any] func(cmdPtr CmdPtrType, dataPtr DataPtrType, value DataType) string
 )
 
 type (
 
 	// InputReader is an interface of a reader that will be used for Input ( ',' In command).
-	// Read() has a string parameter – a hint that might be used in case when user enters values.
-	InputReader[DataType constraints.Signed] interface {
-		Read(string) (DataType, error)
+	// Read() has a hint parameter that might be used in case when user enters values.
+	InputReader[DataType any] interface {
+		Read(prompt.Hint[DataType]) (DataType, error)
 		Close() error
 	}
 
 	// OutputWriter is an interface that is used for Output ('.' Out command).
-	OutputWriter[DataType constraints.Signed] interface {
+	OutputWriter[DataType any] interface {
 		Write(DataType) error
 		Close() error
 	}
 )
 
+// EOFPolicy defines what In (',') command does when InputReader returns io.EOF.
+type EOFPolicy int
+
+const (
+	// EOFError makes Run fail with the reader error. It's the default policy.
+	EOFError EOFPolicy = iota
+
+	// EOFZero sets the current cell to 0
+	EOFZero
+
+	// EOFMinusOne sets the current cell to -1. Unsigned cells get their maximum value.
+	EOFMinusOne
+
+	// EOFNoChange leaves the current cell unchanged
+	EOFNoChange
+)
+
+// eofPolicyNames are names of EOF policies that are used by ParseEOFPolicy and String
+var eofPolicyNames = map[EOFPolicy]string{
+	EOFError:    "error",
+	EOFZero:     "zero",
+	EOFMinusOne: "minus-one",
+	EOFNoChange: "no-change",
+}
+
+func (p EOFPolicy) String() string {
+	if name, ok := eofPolicyNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("EOFPolicy(%d)", int(p))
+}
+
+// ParseEOFPolicy returns EOF policy by its name: error, zero, minus-one or no-change.
+func ParseEOFPolicy(s string) (EOFPolicy, error) {
+	for p, name := range eofPolicyNames {
+		if strings.EqualFold(s, name) {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown EOF policy: %s", s)
+}
+
 const (
 	DefaultDataSize = 4096
 )
 
+var (
+	// ErrForbiddenCmd is returned when loop commands ('[' and ']') are registered or unregistered
+	ErrForbiddenCmd = errors.New("command can't be changed")
+
+	// ErrCmdExists is returned when a command is registered twice
+	ErrCmdExists = errors.New("command is already registered")
+
+	// ErrCmdNotFound is returned when a command that isn't registered is unregistered
+	ErrCmdNotFound = errors.New("command is not registered")
+)
+
 const (
 	CmdShiftRight = CmdType('>')
 	CmdShiftLeft  = CmdType('<')
@@ -127,17 +247,64 @@ const (
 	CmdEndLoop    = CmdType(']')
 )
 
-// New creates an instance of brainfuck interpreter
-func New[DataType constraints.Signed](
+// New creates an instance of brainfuck interpreter with integer cells
+func New[DataType constraints.Integer](
+	dataSize int,
+	input InputReader[DataType],
+	output OutputWriter[DataType],
+) *BfInterpreter[DataType] {
+	return NewWithArithmetic[DataType](dataSize, input, output, IntArithmetic[DataType]{})
+}
+
+// NewBig creates an instance of brainfuck interpreter with arbitrary-precision cells
+func NewBig(
+	dataSize int,
+	input InputReader[*big.Int],
+	output OutputWriter[*big.Int],
+) *BfInterpreter[*big.Int] {
+	return NewWithArithmetic[*big.Int](dataSize, input, output, BigArithmetic{})
+}
+
+// NewWithTape creates an instance of brainfuck interpreter with integer cells that uses tape as memory.
+// Unlike New followed by WithTape it doesn't allocate Data, so it's the constructor for huge tapes.
+func NewWithTape[DataType constraints.Integer](
+	tape Tape[DataType],
+	input InputReader[DataType],
+	output OutputWriter[DataType],
+) *BfInterpreter[DataType] {
+	return newInterpreter[DataType](nil, input, output, IntArithmetic[DataType]{}).WithTape(tape)
+}
+
+// NewWithArithmetic creates an instance of brainfuck interpreter with cells of any type.
+// arith defines how built-in commands change and check cell values.
+func NewWithArithmetic[DataType any](
 	dataSize int,
 	input InputReader[DataType],
 	output OutputWriter[DataType],
+	arith Arithmetic[DataType],
 ) *BfInterpreter[DataType] {
 
 	if dataSize == 0 {
 		dataSize = DefaultDataSize
 	}
 
+	data := make([]DataType, dataSize)
+	zero := arith.Zero()
+	for i := range data {
+		data[i] = zero
+	}
+
+	return newInterpreter(data, input, output, arith)
+}
+
+// newInterpreter creates an instance of brainfuck interpreter with data as memory
+func newInterpreter[DataType any](
+	data []DataType,
+	input InputReader[DataType],
+	output OutputWriter[DataType],
+	arith Arithmetic[DataType],
+) *BfInterpreter[DataType] {
+
 	opMap := map[CmdType]OpFunc[DataType]{
 		CmdShiftRight: opShiftRight[DataType],
 		CmdShiftLeft:  opShiftLeft[DataType],
@@ -149,20 +316,41 @@ func New[DataType constraints.Signed](
 		CmdEndLoop:    opEndLoop[DataType],
 	}
 
-	return &BfInterpreter[DataType]{
-		Data:      make([]DataType, dataSize),
+	bf := &BfInterpreter[DataType]{
+		Data:      data,
 		Output:    output,
 		Input:     input,
 		opMap:     opMap,
 		loopStack: stack.BuildStack[CmdPtrType](),
+		arith:     arith,
+	}
+
+	bf.promptFormatter = bf.defaultPrompt
+
+	return bf
+}
+
+// DefaultPrompt is a default PromptFormatter. It mentions the position of In command.
+func DefaultPrompt[DataType any](cmdPtr CmdPtrType, _ DataPtrType, _ DataType) string {
+	return fmt.Sprintf("enter value [#cmd: %d]", cmdPtr)
+}
+
+// defaultPrompt is DefaultPrompt that adds the source position of In command when a source map is set
+func (bf *BfInterpreter[DataType]) defaultPrompt(cmdPtr CmdPtrType, dataPtr DataPtrType, value DataType) string {
+	if pos, ok := bf.positionOf(cmdPtr); ok {
+		return fmt.Sprintf("enter value [#cmd: %d, %s]", cmdPtr, pos)
 	}
+
+	return DefaultPrompt(cmdPtr, dataPtr, value)
 }
 
 // WithCmd allows to add or overload commands.
 // Loop start and end commands ('[' and ']') can't be overloaded.
 // This restriction is done because these commands change internal interpreter state aside of explicit
 // Data, CmdPtr and DataPtr. Opening access to the rest of variables can make interpreter more vulnerable and behaviour undefined.
-// If you need to implement other loop logics, pleases create a new commands (i.e. '{' and '}').
+// WithCmd ignores such attempts silently, use RegisterCmd to get an error.
+// If you need to implement other loop logics, pleases create a new commands (i.e. '{' and '}')
+// with control-flow methods: PushFrame, PopFrame, TopFrame, ScanForward and JumpToMatching.
 //
 // cmd CmdType – is a command that may be used in the code
 //
@@ -174,76 +362,356 @@ func (bf *BfInterpreter[DataType]) WithCmd(cmd CmdType, opFunc OpFunc[DataType])
 	// Overloading these commands may lead to memory leaks and undefined behaviour that will be hard to detect.
 	if cmd != CmdStartLoop && cmd != CmdEndLoop {
 		bf.opMap[cmd] = opFunc
+		bf.handlers = nil
 	}
 
 	return bf
 }
 
+// WithEOFPolicy sets what In (',') command does when Input reaches the end of data.
+// Readers report the end of data with io.EOF, e.g. reader.ChanReader does it when its channel is closed.
+func (bf *BfInterpreter[DataType]) WithEOFPolicy(policy EOFPolicy) *BfInterpreter[DataType] {
+	bf.eofPolicy = policy
+	return bf
+}
+
+// WithPrompt sets a formatter for prompt texts that are passed to Input.
+// The default one is DefaultPrompt that also mentions the source position when a source map is set.
+func (bf *BfInterpreter[DataType]) WithPrompt(formatter PromptFormatter[DataType]) *BfInterpreter[DataType] {
+	bf.promptFormatter = formatter
+	return bf
+}
+
+// WithoutPrompt disables prompts. Input still gets a hint but its text is empty.
+func (bf *BfInterpreter[DataType]) WithoutPrompt() *BfInterpreter[DataType] {
+	bf.promptFormatter = nil
+	return bf
+}
+
+// WithTape sets an alternative memory implementation, i.e. SparseTape for huge address spaces.
+// Data is released and isn't used after that.
+func (bf *BfInterpreter[DataType]) WithTape(tape Tape[DataType]) *BfInterpreter[DataType] {
+	bf.Tape = tape
+	bf.Data = nil
+
+	return bf
+}
+
+// RegisterCmd adds a new command. Unlike WithCmd it reports problems instead of ignoring them:
+// it returns ErrForbiddenCmd for loop commands ('[' and ']') and ErrCmdExists if the command has a handler already.
+// To overload a command it should be unregistered first.
+func (bf *BfInterpreter[DataType]) RegisterCmd(cmd CmdType, opFunc OpFunc[DataType]) error {
+	if cmd == CmdStartLoop || cmd == CmdEndLoop {
+		return fmt.Errorf("%w: '%c'", ErrForbiddenCmd, cmd)
+	}
+
+	if _, ok := bf.opMap[cmd]; ok {
+		return fmt.Errorf("%w: '%c'", ErrCmdExists, cmd)
+	}
+
+	bf.opMap[cmd] = opFunc
+	bf.handlers = nil
+
+	return nil
+}
+
+// UnregisterCmd removes a command handler, so the command will be ignored as a comment.
+// It returns ErrForbiddenCmd for loop commands ('[' and ']') and ErrCmdNotFound if the command has no handler.
+func (bf *BfInterpreter[DataType]) UnregisterCmd(cmd CmdType) error {
+	if cmd == CmdStartLoop || cmd == CmdEndLoop {
+		return fmt.Errorf("%w: '%c'", ErrForbiddenCmd, cmd)
+	}
+
+	if _, ok := bf.opMap[cmd]; !ok {
+		return fmt.Errorf("%w: '%c'", ErrCmdNotFound, cmd)
+	}
+
+	delete(bf.opMap, cmd)
+	bf.handlers = nil
+
+	return nil
+}
+
+// Use adds middlewares that wrap all handlers, built-in and custom ones, including those registered later.
+// Middlewares are applied in the order they were added: the first one is the outermost and is called first.
+func (bf *BfInterpreter[DataType]) Use(middlewares ...Middleware[DataType]) *BfInterpreter[DataType] {
+	bf.middlewares = append(bf.middlewares, middlewares...)
+	bf.handlers = nil
+
+	return bf
+}
+
+// CurrentCmd returns the command that is being processed. It's useful for middlewares.
+func (bf *BfInterpreter[DataType]) CurrentCmd() CmdType {
+	return bf.cmd
+}
+
+// handler returns a command handler wrapped with middlewares
+func (bf *BfInterpreter[DataType]) handler(cmd CmdType) (OpFunc[DataType], bool) {
+	if len(bf.middlewares) == 0 {
+		opFunc, ok := bf.opMap[cmd]
+		return opFunc, ok
+	}
+
+	if bf.handlers == nil {
+		bf.handlers = make(map[CmdType]OpFunc[DataType], len(bf.opMap))
+
+		for c, opFunc := range bf.opMap {
+			for i := len(bf.middlewares) - 1; i >= 0; i-- {
+				opFunc = bf.middlewares[i](opFunc)
+			}
+
+			bf.handlers[c] = opFunc
+		}
+	}
+
+	opFunc, ok := bf.handlers[cmd]
+	return opFunc, ok
+}
+
+// Commands returns the sorted list of commands that have handlers.
+func (bf *BfInterpreter[DataType]) Commands() []CmdType {
+	cmds := make([]CmdType, 0, len(bf.opMap))
+	for cmd := range bf.opMap {
+		cmds = append(cmds, cmd)
+	}
+
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i] < cmds[j] })
+
+	return cmds
+}
+
 // Run starts interpreting brainfuck code. It reads commands one by one from commands reader.
+// It returns the memory content. When Tape is set the content is returned only if Tape implements SliceTape.
+// Memory is kept between runs, use Reset to clear it.
 func (bf *BfInterpreter[DataType]) Run(commands io.Reader) ([]DataType, error) {
 
-	bf.CmdPtr = 0
-	bf.DataPtr = 0
+	bf.resetControl()
+
+	// the tape may keep the pointer of a previous run
+	if pt, ok := bf.Tape.(PointerTape[DataType]); ok {
+		bf.DataPtr = pt.Pointer()
+		defer func() { pt.SetPointer(bf.DataPtr) }()
+	}
 
+	if bf.inputFromCommands != nil {
+		program, input := splitCommands(commands, CmdStop)
+		commands = program
+
+		// the input is taken from the commands of this run only
+		defer func(input InputReader[DataType]) { bf.Input = input }(bf.Input)
+		bf.Input = bf.inputFromCommands(input)
+	}
+
+	bf.src = &cmdSource{r: commands}
+
+	var err error
+	if bf.forks != nil {
+		err = bf.runThreads()
+	} else {
+		err = bf.runCommands()
+	}
+
+	// extensions restore the state that is returned and saved, i.e. the main tape
+	for _, hook := range bf.finishHooks {
+		hook()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bf.tapeData(), nil
+}
+
+// runCommands processes commands until they are over
+func (bf *BfInterpreter[DataType]) runCommands() error {
 	for {
+		done, err := bf.step()
+		if err != nil {
+			return err
+		}
 
-		var cmd CmdType
-		var ok bool
+		if done {
+			return nil
+		}
+	}
+}
+
+// step processes one command. done is true when commands are over.
+func (bf *BfInterpreter[DataType]) step() (done bool, err error) {
+	cmd, ok, err := bf.fetchCmd(bf.CmdPtr)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		return true, nil
+	}
+
+	// ignoring commands without correspondent handler
+	if opFunc, ok := bf.handler(cmd); ok {
+		cmdPtr := bf.CmdPtr
+		bf.cmd = cmd
 
-		// trying to read a command from cache
-		if bf.cmdCache != nil {
-			cmd, ok = bf.cmdCache[bf.CmdPtr]
+		// processing command
+		if err := opFunc(bf); err != nil {
+			return false, bf.cmdError(cmdPtr, cmd, err)
 		}
 
-		// no cached command, let's get a new one from the reader
-		if !ok {
-			cmdBuffer := make([]byte, 1)
+		bf.cacheCmd(cmdPtr, cmd)
+	}
 
-			_, err := commands.Read(cmdBuffer)
-			if errors.Is(err, io.EOF) {
-				return bf.Data, nil
-			}
+	bf.CmdPtr++
 
-			if err != nil {
-				return nil, fmt.Errorf("failed to read command: %w", err)
-			}
+	return false, nil
+}
+
+// cmdSource is a stream of commands with a cache of commands that may be repeated.
+// Forked interpreters (see EnableBrainfork) share the same source.
+type cmdSource struct {
+	r     io.Reader
+	cache CmdCache
 
-			cmd = CmdType(cmdBuffer[0])
+	// next is the position of the command that will be read from r
+	next CmdPtrType
+}
+
+// fetchCmd returns the command at ptr. Commands that were read already are taken from cache,
+// otherwise commands are read from the stream until ptr. ok is false when commands are over.
+func (bf *BfInterpreter[DataType]) fetchCmd(ptr CmdPtrType) (cmd CmdType, ok bool, err error) {
+
+	// trying to read a command from cache
+	if cmd, ok := bf.src.cache[ptr]; ok {
+		return cmd, true, nil
+	}
+
+	if ptr < bf.src.next {
+		return 0, false, fmt.Errorf("command [#cmd: %d] is not cached", ptr)
+	}
+
+	// no cached command, let's get a new one from the reader
+	cmdBuffer := make([]byte, 1)
+
+	for bf.src.next <= ptr {
+		_, err = io.ReadFull(bf.src.r, cmdBuffer)
+		if errors.Is(err, io.EOF) {
+			return 0, false, nil
 		}
 
-		// ignoring commands without correspondent handler
-		opFunc, ok := bf.opMap[cmd]
-		if ok {
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read command: %w", err)
+		}
 
-			// When the loop starts we're starting to cache commands
-			if cmd == CmdStartLoop && bf.cmdCache == nil {
-				bf.cmdCache = make(CmdCache)
-				bf.cmdCache[bf.CmdPtr] = cmd
-			}
+		cmd = CmdType(cmdBuffer[0])
+		bf.cacheCmd(bf.src.next, cmd)
+		bf.src.next++
+	}
 
-			// If we're in loop we're caching every command
-			if bf.loopStack.Len() > 0 && bf.cmdCache != nil {
-				bf.cmdCache[bf.CmdPtr] = cmd
-			}
+	return cmd, true, nil
+}
 
-			// processing command
-			if err := opFunc(bf); err != nil {
-				return nil, fmt.Errorf("failed to process [#cmd: %d]: %w", bf.CmdPtr, err)
-			}
+// cacheCmd caches the command if we're in a loop (or in any other control frame), as it may be repeated.
+// Cache is not necessary anymore when we finish the topmost loop, unless commands are retained.
+func (bf *BfInterpreter[DataType]) cacheCmd(ptr CmdPtrType, cmd CmdType) {
+	if bf.loopStack.Len() == 0 && !bf.retainCmds {
+		bf.src.cache = nil
+		return
+	}
 
-			// Cache is not necessary anymore when we finish the topmost loop
-			if bf.loopStack.Len() == 0 {
-				bf.cmdCache = nil
-			}
+	if bf.src.cache == nil {
+		bf.src.cache = make(CmdCache)
+	}
+
+	bf.src.cache[ptr] = cmd
+}
+
+// Reset clears memory and the state of the interpreter, so it may be reused for another program.
+// Registered commands and settings (EOF policy, prompt, Input and Output) are kept.
+// Tape is cleared only if it implements ResettableTape, a pointer that is stored by PointerTape is set to 0.
+func (bf *BfInterpreter[DataType]) Reset() {
+	bf.resetControl()
+
+	if bf.Tape != nil {
+		if rt, ok := bf.Tape.(ResettableTape[DataType]); ok {
+			rt.Reset()
+		}
+
+		if pt, ok := bf.Tape.(PointerTape[DataType]); ok {
+			pt.SetPointer(0)
 		}
 
-		bf.CmdPtr++
+		return
+	}
+
+	zero := bf.arith.Zero()
+	for i := range bf.Data {
+		bf.Data[i] = zero
+	}
+}
+
+// resetControl clears pointers, loops and commands cache that may be left by a previous (i.e. aborted) run.
+func (bf *BfInterpreter[DataType]) resetControl() {
+	bf.CmdPtr = 0
+	bf.DataPtr = 0
+	bf.loopStack = stack.BuildStack[CmdPtrType]()
+	bf.src = nil
+	bf.currentLoopEnd = 0
+
+	for _, hook := range bf.resetHooks {
+		hook()
+	}
+}
+
+// Cell returns the value of the current cell
+func (bf *BfInterpreter[DataType]) Cell() DataType {
+	return bf.cellAt(bf.DataPtr)
+}
+
+// cellAt returns the value of any cell
+func (bf *BfInterpreter[DataType]) cellAt(ptr DataPtrType) DataType {
+	if bf.Tape != nil {
+		return bf.Tape.Get(ptr)
 	}
+
+	return bf.Data[ptr]
+}
+
+// SetCell sets the value of the current cell
+func (bf *BfInterpreter[DataType]) SetCell(v DataType) {
+	if bf.Tape != nil {
+		bf.Tape.Set(bf.DataPtr, v)
+		return
+	}
+
+	bf.Data[bf.DataPtr] = v
+}
+
+// TapeLen returns the number of cells in memory
+func (bf *BfInterpreter[DataType]) TapeLen() DataPtrType {
+	if bf.Tape != nil {
+		return bf.Tape.Len()
+	}
+
+	return DataPtrType(len(bf.Data))
+}
+
+// tapeData returns the memory content as a slice
+func (bf *BfInterpreter[DataType]) tapeData() []DataType {
+	if bf.Tape == nil {
+		return bf.Data
+	}
+
+	if st, ok := bf.Tape.(SliceTape[DataType]); ok {
+		return st.Slice()
+	}
+
+	return nil
 }
 
 // opShiftRight is default handler for ShiftRight ('>') command
-func opShiftRight[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
-	if bf.DataPtr >= DataPtrType(len(bf.Data)-1) {
+func opShiftRight[DataType any](bf *BfInterpreter[DataType]) error {
+	if bf.DataPtr >= bf.TapeLen()-1 {
 		return fmt.Errorf("shift+ moves out of boundary")
 	}
 	bf.DataPtr++
@@ -252,7 +720,7 @@ func opShiftRight[DataType constraints.Signed](bf *BfInterpreter[DataType]) erro
 }
 
 // opShiftLeft is default handler for ShiftLeft ('<') command
-func opShiftLeft[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
+func opShiftLeft[DataType any](bf *BfInterpreter[DataType]) error {
 	if bf.DataPtr <= 0 {
 		return fmt.Errorf("shift- moves out of boundary")
 	}
@@ -262,21 +730,21 @@ func opShiftLeft[DataType constraints.Signed](bf *BfInterpreter[DataType]) error
 }
 
 // opPlus is default handler for Plus ('+') command
-func opPlus[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
-	bf.Data[bf.DataPtr] += 1
+func opPlus[DataType any](bf *BfInterpreter[DataType]) error {
+	bf.SetCell(bf.arith.Inc(bf.Cell()))
 
 	return nil
 }
 
 // opMinus is default handler for Minus ('-') command
-func opMinus[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
-	bf.Data[bf.DataPtr] -= 1
+func opMinus[DataType any](bf *BfInterpreter[DataType]) error {
+	bf.SetCell(bf.arith.Dec(bf.Cell()))
 	return nil
 }
 
 // opOut is default handler for Out ('.') command
-func opOut[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
-	v := bf.Data[bf.DataPtr]
+func opOut[DataType any](bf *BfInterpreter[DataType]) error {
+	v := bf.Cell()
 	if err := bf.Output.Write(v); err != nil {
 		return fmt.Errorf("failed to write value: %w", err)
 	}
@@ -285,19 +753,48 @@ func opOut[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
 }
 
 // opIn is default handler for In (',') command
-func opIn[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
-	rn, err := bf.Input.Read(fmt.Sprintf("enter value [#cmd: %d]", bf.CmdPtr))
+func opIn[DataType any](bf *BfInterpreter[DataType]) error {
+	hint := prompt.Hint[DataType]{
+		CmdPtr:  int(bf.CmdPtr),
+		DataPtr: int(bf.DataPtr),
+		Value:   bf.Cell(),
+	}
+
+	if pos, ok := bf.SourcePosition(); ok {
+		hint.Position = &pos
+	}
+
+	if bf.promptFormatter != nil {
+		hint.Text = bf.promptFormatter(bf.CmdPtr, bf.DataPtr, hint.Value)
+	}
+
+	rn, err := bf.Input.Read(hint)
+
+	if errors.Is(err, io.EOF) && bf.eofPolicy != EOFError {
+		switch bf.eofPolicy {
+		case EOFZero:
+			bf.SetCell(bf.arith.Zero())
+		case EOFMinusOne:
+			bf.SetCell(bf.arith.Dec(bf.arith.Zero()))
+		case EOFNoChange:
+		default:
+			return fmt.Errorf("unknown EOF policy: %d", bf.eofPolicy)
+		}
+
+		return nil
+	}
+
 	if err != nil {
 		return fmt.Errorf("failed to read value: %w", err)
 	}
 
-	bf.Data[bf.DataPtr] = rn
+	bf.SetCell(rn)
 
 	return nil
 }
 
 // opStartLoop is a handler for StartLoop ('[') command
-func opStartLoop[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
+func opStartLoop[DataType any](bf *BfInterpreter[DataType]) error {
 
 	// what's on top of the stack?
 	loop := bf.loopStack.Get()
@@ -308,18 +805,25 @@ func opStartLoop[DataType constraints.Signed](bf *BfInterpreter[DataType]) error
 	}
 
 	// should we stay in loop?
-	if bf.Data[bf.DataPtr] != 0 {
+	if !bf.arith.IsZero(bf.Cell()) {
 		return nil
 	}
 
 	_ = bf.loopStack.Pop()
+
+	// The end of the loop is known only if we've got here from it.
+	// Otherwise, the loop is skipped at all and we're looking for its end.
+	if loop == nil || *loop != bf.CmdPtr {
+		return bf.JumpToMatching(CmdStartLoop, CmdEndLoop)
+	}
+
 	bf.CmdPtr = bf.currentLoopEnd // bf.CmdPtr will be incremented
 
 	return nil
 }
 
 // opEndLoop is a handler for EndLoop (']') command
-func opEndLoop[DataType constraints.Signed](bf *BfInterpreter[DataType]) error {
+func opEndLoop[DataType any](bf *BfInterpreter[DataType]) error {
 	loop := bf.loopStack.Get()
 
 	if loop == nil {
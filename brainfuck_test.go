@@ -2,10 +2,18 @@ package brainfuck
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/sourcemap"
 	"github.com/yurii-vyrovyi/brainfuck/stack"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
@@ -199,7 +207,7 @@ func TestBfInterpreter_Operations(t *testing.T) {
 			mockInputReader := NewMockTestInputReader(mockCtrl)
 			mockInputReader.EXPECT().Close().AnyTimes().Return(nil)
 			mockInputReader.EXPECT().Read(gomock.Any()).AnyTimes().
-				DoAndReturn(func(string) (TestDataType, error) {
+				DoAndReturn(func(TestHint) (TestDataType, error) {
 
 					if test.srcInError != nil {
 						return 0, test.srcInError
@@ -416,6 +424,20 @@ func TestBfInterpreter_Run(t *testing.T) {
 			expOutput:   []TestDataType{3, 2, 4, 6, 8, 10, 12, 0},
 			expData:     []TestDataType{0, 0, 3, 0, 0, 12, 0},
 		},
+
+		"skipped loop": {
+			srcCommands: []byte(`++[>[+++[-]]+<-]>.`),
+			srcInput:    nil,
+			expOutput:   []TestDataType{1},
+			expData:     []TestDataType{0, 1},
+		},
+
+		"comments in loop": {
+			srcCommands: []byte("++[\n>+++ add three\n<-]>."),
+			srcInput:    nil,
+			expOutput:   []TestDataType{6},
+			expData:     []TestDataType{0, 6},
+		},
 	}
 
 	//nolint:paralleltest
@@ -432,7 +454,7 @@ func TestBfInterpreter_Run(t *testing.T) {
 			mockInputReader := NewMockTestInputReader(mockCtrl)
 			mockInputReader.EXPECT().Close().AnyTimes().Return(nil)
 			mockInputReader.EXPECT().Read(gomock.Any()).AnyTimes().
-				DoAndReturn(func(string) (TestDataType, error) {
+				DoAndReturn(func(TestHint) (TestDataType, error) {
 					if cntInput >= len(test.srcInput) {
 						return 0, errors.New("no more input")
 					}
@@ -464,3 +486,454 @@ func TestBfInterpreter_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestBfInterpreter_RunSliceIO(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		srcCommands string
+		srcInput    string
+
+		expOutput string
+	}
+
+	tests := map[string]Test{
+		"echo": {
+			srcCommands: `,.,.,.`,
+			srcInput:    "abc",
+			expOutput:   "abc",
+		},
+
+		"uppercase": {
+			srcCommands: `,>++++[<-------->-]<.,>++++[<-------->-]<.`,
+			srcInput:    "hi",
+			expOutput:   "HI",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[TestDataType]()
+
+			bf := New[TestDataType](10, reader.BuildStringReader[TestDataType](test.srcInput), output)
+
+			_, err := bf.Run(strings.NewReader(test.srcCommands))
+			require.NoError(t, err)
+
+			require.Equal(t, test.expOutput, output.String())
+		})
+	}
+}
+
+func TestBfInterpreter_EOFPolicy(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		policy EOFPolicy
+
+		expErr  bool
+		expData []TestDataType
+	}
+
+	tests := map[string]Test{
+		"Error": {
+			policy: EOFError,
+			expErr: true,
+		},
+
+		"Zero": {
+			policy:  EOFZero,
+			expData: []TestDataType{0, 0},
+		},
+
+		"MinusOne": {
+			policy:  EOFMinusOne,
+			expData: []TestDataType{-1, 0},
+		},
+
+		"NoChange": {
+			policy:  EOFNoChange,
+			expData: []TestDataType{3, 0},
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := ParseEOFPolicy(test.policy.String())
+			require.NoError(t, err)
+			require.Equal(t, test.policy, policy)
+
+			bf := New[TestDataType](2, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]()).
+				WithEOFPolicy(policy)
+
+			resData, err := bf.Run(strings.NewReader(`+++,`))
+
+			if test.expErr {
+				require.ErrorIs(t, err, io.EOF)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, cmp.Equal(test.expData, resData))
+		})
+	}
+}
+
+func TestBfInterpreter_ChanPipeline(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ch := make(chan TestDataType)
+
+	// producer echoes its input to the channel
+	producerOut := writer.BuildChanWriter[TestDataType](ctx, ch)
+	producer := New[TestDataType](10, reader.BuildStringReader[TestDataType]("abc"), producerOut).
+		WithEOFPolicy(EOFZero)
+
+	// consumer increments every value it receives
+	output := writer.BuildSliceWriter[TestDataType]()
+	consumer := New[TestDataType](10, reader.BuildChanReader[TestDataType](ctx, ch), output).
+		WithEOFPolicy(EOFZero)
+
+	producerErr := make(chan error, 1)
+
+	go func() {
+		_, err := producer.Run(strings.NewReader(`,[.,]`))
+		_ = producerOut.Close()
+		producerErr <- err
+	}()
+
+	_, err := consumer.Run(strings.NewReader(`,[+.,]`))
+	require.NoError(t, err)
+	require.NoError(t, <-producerErr)
+
+	require.Equal(t, "bcd", output.String())
+}
+
+func TestBfInterpreter_Prompt(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		setup func(bf *BfInterpreter[TestDataType])
+
+		expHint TestHint
+	}
+
+	tests := map[string]Test{
+		"Default": {
+			setup: func(bf *BfInterpreter[TestDataType]) {},
+
+			expHint: TestHint{Text: "enter value [#cmd: 3]", CmdPtr: 3, DataPtr: 1, Value: 2},
+		},
+
+		"Source position": {
+			setup: func(bf *BfInterpreter[TestDataType]) {
+				bf.WithSourceMap(sourcemap.Build([]sourcemap.Position{
+					{File: "main.b", Line: 1, Column: 1},
+					{File: "main.b", Line: 1, Column: 2},
+					{File: "main.b", Line: 1, Column: 3},
+					{File: "main.b", Line: 2, Column: 1},
+				}))
+			},
+
+			expHint: TestHint{
+				Text:     "enter value [#cmd: 3, main.b:2:1]",
+				CmdPtr:   3,
+				Position: &sourcemap.Position{File: "main.b", Line: 2, Column: 1},
+				DataPtr:  1,
+				Value:    2,
+			},
+		},
+
+		"Custom": {
+			setup: func(bf *BfInterpreter[TestDataType]) {
+				bf.WithPrompt(func(cmdPtr CmdPtrType, dataPtr DataPtrType, value TestDataType) string {
+					return fmt.Sprintf("cell %d = %d", dataPtr, value)
+				})
+			},
+
+			expHint: TestHint{Text: "cell 1 = 2", CmdPtr: 3, DataPtr: 1, Value: 2},
+		},
+
+		"Disabled": {
+			setup: func(bf *BfInterpreter[TestDataType]) {
+				bf.WithoutPrompt()
+			},
+
+			expHint: TestHint{CmdPtr: 3, DataPtr: 1, Value: 2},
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+
+			mockInputReader := NewMockTestInputReader(mockCtrl)
+			mockInputReader.EXPECT().Read(test.expHint).Times(1).Return(TestDataType(5), nil)
+
+			bf := New[TestDataType](2, mockInputReader, writer.BuildSliceWriter[TestDataType]())
+			test.setup(bf)
+
+			resData, err := bf.Run(strings.NewReader(`>++,`))
+			require.NoError(t, err)
+			require.True(t, cmp.Equal([]TestDataType{0, 5}, resData))
+		})
+	}
+}
+
+func TestBfInterpreter_CellTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uint8 wraps around", func(t *testing.T) {
+		t.Parallel()
+
+		output := writer.BuildSliceWriter[uint8]()
+		bf := New[uint8](2, reader.BuildSliceReader[uint8](), output)
+
+		resData, err := bf.Run(strings.NewReader(`-.>-[-]+.`))
+		require.NoError(t, err)
+
+		require.Equal(t, []uint8{255, 1}, output.Values())
+		require.Equal(t, []uint8{255, 1}, resData)
+	})
+
+	t.Run("big.Int doesn't overflow", func(t *testing.T) {
+		t.Parallel()
+
+		bf := NewBig(2, reader.BuildSliceReader[*big.Int](), writer.BuildStdOutWriter[*big.Int]())
+
+		huge, _ := new(big.Int).SetString("1267650600228229401496703205376", 10) // 2^100
+		bf.Data[1] = huge
+
+		resData, err := bf.Run(strings.NewReader(`->+`))
+		require.NoError(t, err)
+
+		require.Equal(t, "-1", resData[0].String())
+		require.Equal(t, "1267650600228229401496703205377", resData[1].String())
+		require.Equal(t, "1267650600228229401496703205376", huge.String()) // cells are not changed in place
+	})
+}
+
+func TestBfInterpreter_Reset(t *testing.T) {
+	t.Parallel()
+
+	output := writer.BuildSliceWriter[TestDataType]()
+
+	bf := New[TestDataType](4, reader.BuildSliceReader[TestDataType](), output).
+		WithCmd('*', func(bf *BfInterpreter[TestDataType]) error {
+			bf.SetCell(bf.Cell() * 2)
+			return nil
+		})
+
+	// the run is aborted inside a loop when the input is over
+	_, err := bf.Run(strings.NewReader(`+[>+<,]`))
+	require.Error(t, err)
+
+	bf.Reset()
+	require.True(t, cmp.Equal([]TestDataType{0, 0, 0, 0}, bf.Data))
+
+	resData, err := bf.Run(strings.NewReader(`+++*[>+<-]>.`))
+	require.NoError(t, err)
+	require.True(t, cmp.Equal([]TestDataType{0, 6, 0, 0}, resData))
+	require.Equal(t, []TestDataType{6}, output.Values())
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	pool := NewPool(func() *BfInterpreter[TestDataType] {
+		return New[TestDataType](4, nil, nil)
+	})
+
+	for i := 0; i < 3; i++ {
+		bf := pool.Get()
+
+		output := writer.BuildSliceWriter[TestDataType]()
+		bf.Output = output
+
+		_, err := bf.Run(strings.NewReader(`++.`))
+		require.NoError(t, err)
+		require.Equal(t, []TestDataType{2}, output.Values())
+
+		pool.Put(bf)
+	}
+}
+
+func TestBfInterpreter_ControlFlow(t *testing.T) {
+	t.Parallel()
+
+	// '{' and '}' is a do-while loop
+	doWhileStart := func(bf *BfInterpreter[TestDataType]) error {
+		bf.PushFrame(bf.CmdPtr)
+		return nil
+	}
+
+	doWhileEnd := func(bf *BfInterpreter[TestDataType]) error {
+		start, ok := bf.TopFrame()
+		if !ok {
+			return errors.New("no frame to close")
+		}
+
+		if bf.Cell() != 0 {
+			bf.CmdPtr = start
+			return nil
+		}
+
+		_, _ = bf.PopFrame()
+		return nil
+	}
+
+	// '(' and ')' is a condition that runs its body if the cell is not zero
+	ifStart := func(bf *BfInterpreter[TestDataType]) error {
+		if bf.Cell() == 0 {
+			return bf.JumpToMatching('(', ')')
+		}
+		return nil
+	}
+
+	type Test struct {
+		srcCommands string
+
+		expErr    bool
+		expOutput []TestDataType
+	}
+
+	tests := map[string]Test{
+		"do-while runs once": {
+			srcCommands: `{+.-}`,
+			expOutput:   []TestDataType{1},
+		},
+
+		"do-while repeats": {
+			srcCommands: `+++{.-}.`,
+			expOutput:   []TestDataType{3, 2, 1, 0},
+		},
+
+		"nested do-while": {
+			srcCommands: `++{>++{.-}<-}`,
+			expOutput:   []TestDataType{2, 1, 2, 1},
+		},
+
+		"skipped condition": {
+			srcCommands: `(+(+).)+.`,
+			expOutput:   []TestDataType{1},
+		},
+
+		"condition in loop": {
+			srcCommands: `++[>(.)+<-]`,
+			expOutput:   []TestDataType{1},
+		},
+
+		"unmatched condition": {
+			srcCommands: `(+`,
+			expErr:      true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[TestDataType]()
+
+			bf := New[TestDataType](4, reader.BuildSliceReader[TestDataType](), output).
+				WithCmd('{', doWhileStart).
+				WithCmd('}', doWhileEnd).
+				WithCmd('(', ifStart).
+				WithCmd(')', func(*BfInterpreter[TestDataType]) error { return nil })
+
+			_, err := bf.Run(strings.NewReader(test.srcCommands))
+
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, output.Values())
+		})
+	}
+}
+
+func TestBfInterpreter_RegisterCmd(t *testing.T) {
+	t.Parallel()
+
+	noop := func(*BfInterpreter[TestDataType]) error { return nil }
+
+	bf := New[TestDataType](4, nil, nil)
+
+	require.ErrorIs(t, bf.RegisterCmd(CmdStartLoop, noop), ErrForbiddenCmd)
+	require.ErrorIs(t, bf.UnregisterCmd(CmdEndLoop), ErrForbiddenCmd)
+
+	require.ErrorIs(t, bf.RegisterCmd(CmdPlus, noop), ErrCmdExists)
+	require.ErrorIs(t, bf.UnregisterCmd('*'), ErrCmdNotFound)
+
+	require.NoError(t, bf.UnregisterCmd(CmdOut))
+	require.NoError(t, bf.UnregisterCmd(CmdIn))
+	require.NoError(t, bf.RegisterCmd('*', noop))
+
+	require.Equal(t, []CmdType{'*', '+', '-', '<', '>', '[', ']'}, bf.Commands())
+}
+
+func TestBfInterpreter_Use(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+
+	logger := func(name string) Middleware[TestDataType] {
+		return func(next OpFunc[TestDataType]) OpFunc[TestDataType] {
+			return func(bf *BfInterpreter[TestDataType]) error {
+				log = append(log, fmt.Sprintf("%s:%c", name, bf.CurrentCmd()))
+				return next(bf)
+			}
+		}
+	}
+
+	errForbidden := errors.New("input is forbidden")
+
+	noInput := func(next OpFunc[TestDataType]) OpFunc[TestDataType] {
+		return func(bf *BfInterpreter[TestDataType]) error {
+			if bf.CurrentCmd() == CmdIn {
+				return errForbidden
+			}
+			return next(bf)
+		}
+	}
+
+	output := writer.BuildSliceWriter[TestDataType]()
+
+	bf := New[TestDataType](4, reader.BuildSliceReader[TestDataType](1), output).
+		Use(logger("outer"), logger("inner")).
+		WithCmd('*', func(bf *BfInterpreter[TestDataType]) error {
+			bf.SetCell(bf.Cell() * 2)
+			return nil
+		})
+
+	_, err := bf.Run(strings.NewReader(`+*.`))
+	require.NoError(t, err)
+	require.Equal(t, []TestDataType{2}, output.Values())
+	require.Equal(t, []string{"outer:+", "inner:+", "outer:*", "inner:*", "outer:.", "inner:."}, log)
+
+	bf.Use(noInput)
+
+	_, err = bf.Run(strings.NewReader(`,`))
+	require.ErrorIs(t, err, errForbidden)
+}
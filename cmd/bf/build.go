@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/codegen/c"
+	"github.com/yurii-vyrovyi/brainfuck/codegen/llvm"
+	"github.com/yurii-vyrovyi/brainfuck/codegen/wasm"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/preprocess"
+)
+
+// generators are code generators by target name
+var generators = map[string]func(w io.Writer, prog ir.Program, opts codegen.Options) error{
+	"c":    c.Generate,
+	"llvm": llvm.Generate,
+	"wasm": wasm.Generate,
+	"wat":  wasm.GenerateText,
+}
+
+// machineFlags registers flags of the machine that generated code implements
+func machineFlags(fs *flag.FlagSet) func() (codegen.Options, error) {
+	defaults := codegen.DefaultOptions()
+
+	cellBits := fs.Int("cell", defaults.CellBits, "cell width in bits: 8, 16, 32 or 64")
+	signed := fs.Bool("signed", defaults.Signed, "signed cells")
+	tapeSize := fs.Int("tape", defaults.TapeSize, "tape size in cells")
+	eof := fs.String("eof", defaults.EOFPolicy.String(), "EOF policy: error, zero, minus-one or no-change")
+	bounds := fs.Bool("bounds", defaults.BoundsCheck, "fail when the data pointer moves out of the tape")
+
+	return func() (codegen.Options, error) {
+		policy, err := brainfuck.ParseEOFPolicy(*eof)
+		if err != nil {
+			return codegen.Options{}, err
+		}
+
+		opts := codegen.Options{
+			CellBits:    *cellBits,
+			Signed:      *signed,
+			TapeSize:    *tapeSize,
+			EOFPolicy:   policy,
+			BoundsCheck: *bounds,
+		}
+
+		return opts, opts.Validate()
+	}
+}
+
+// programFlags registers flags of reading a program
+func programFlags(fs *flag.FlagSet) func(stdin io.Reader) (ir.Program, error) {
+	optimize := fs.Bool("O", true, "optimize the program")
+	pp := fs.Bool("preprocess", false, "run the preprocessor (includes, macros) on the program file")
+
+	return func(stdin io.Reader) (ir.Program, error) {
+		src, err := readProgram(fs.Arg(0), stdin, *pp)
+		if err != nil {
+			return nil, err
+		}
+
+		prog, err := ir.Parse(bytes.NewReader(src.Code))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse program: %w", err)
+		}
+
+		if *optimize {
+			prog = ir.Optimize(prog)
+		}
+
+		return prog, nil
+	}
+}
+
+func build(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+
+	target := fs.String("target", "", "target: c, llvm (LLVM IR), wasm (binary WebAssembly module) or wat (WebAssembly text)")
+	outFile := fs.String("o", "", "output file (stdout by default)")
+	options := machineFlags(fs)
+	program := programFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return err
+	}
+
+	generate, ok := generators[*target]
+	if !ok {
+		return fmt.Errorf("unknown target: %q", *target)
+	}
+
+	opts, err := options()
+	if err != nil {
+		return err
+	}
+
+	prog, err := program(stdin)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := generate(&out, prog, opts); err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	return writeOutput(*outFile, stdout, out.Bytes())
+}
+
+// readProgram reads a program from a file or from stdin if the file name is empty or "-".
+// The result has a source map only if the program is preprocessed.
+func readProgram(fileName string, stdin io.Reader, pp bool) (*preprocess.Result, error) {
+	if pp {
+		if fileName == "" || fileName == "-" {
+			return nil, fmt.Errorf("preprocessor needs a program file")
+		}
+
+		res, err := preprocess.ProcessFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to preprocess program: %w", err)
+		}
+
+		return res, nil
+	}
+
+	if fileName == "" || fileName == "-" {
+		code, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read program: %w", err)
+		}
+
+		return &preprocess.Result{Code: code}, nil
+	}
+
+	code, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read program: %w", err)
+	}
+
+	return &preprocess.Result{Code: code}, nil
+}
+
+// writeOutput writes generated code to a file or to stdout if the file name is empty
+func writeOutput(fileName string, stdout io.Writer, data []byte) error {
+	if fileName == "" {
+		_, err := stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(fileName, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yurii-vyrovyi/brainfuck/codegen/golang"
+)
+
+func genGo(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gen-go", flag.ContinueOnError)
+
+	// go generate sets GOPACKAGE to the package of the file with the directive
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "package name ($GOPACKAGE by default)")
+	funcName := fs.String("func", "Run", "function name")
+	noPrompt := fs.Bool("no-prompt", false, "pass hints without text to the reader")
+	outFile := fs.String("o", "", "output file (stdout by default)")
+	options := machineFlags(fs)
+	program := programFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return err
+	}
+
+	opts, err := options()
+	if err != nil {
+		return err
+	}
+
+	prog, err := program(stdin)
+	if err != nil {
+		return err
+	}
+
+	cfg := golang.Config{
+		Package:  *pkg,
+		Func:     *funcName,
+		NoPrompt: *noPrompt,
+	}
+
+	var out bytes.Buffer
+	if err := golang.Generate(&out, prog, opts, cfg); err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	return writeOutput(*outFile, stdout, out.Bytes())
+}
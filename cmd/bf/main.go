@@ -0,0 +1,59 @@
+// Command bf is a brainfuck toolchain.
+//
+// Usage:
+//
+//	bf run [flags] [file]                              runs a program with the interpreter
+//	bf build --target=c|llvm|wasm|wat [flags] [file]   translates a program to another language
+//	bf gen-go [flags] [file]                           generates a Go function from a program
+//
+// run reads the program input from stdin and writes the tape to stderr when -dump=hex|window|json|csv is set:
+//
+//	bf run -dump=hex -tape=64 kernel.b < input.txt
+//
+// gen-go is meant for go:generate directives:
+//
+//	//go:generate bf gen-go -o kernel_bf.go kernel.b
+//
+// A program is read from stdin when file is omitted or is "-".
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: bf <command> [flags] [file]
+
+commands:
+  run      runs a program with the interpreter (bf run -h for flags)
+  build    translates a program to another language (bf build -h for flags)
+  gen-go   generates a Go function from a program (bf gen-go -h for flags)
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command expected\n%s", usage)
+	}
+
+	switch args[0] {
+	case "run":
+		return runProgram(args[1:], stdin, stdout, stderr)
+	case "build":
+		return build(args[1:], stdin, stdout)
+	case "gen-go":
+		return genGo(args[1:], stdin, stdout)
+	case "help", "-h", "-help", "--help":
+		_, err := io.WriteString(stdout, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command: %s\n%s", args[0], usage)
+	}
+}
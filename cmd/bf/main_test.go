@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Build(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib.b"), []byte("#define out(n) +*@n."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.b"), []byte("#include \"lib.b\"\n@out(3)"), 0644))
+
+	type Test struct {
+		args  []string
+		stdin string
+
+		expErr    bool
+		expOutput []string
+	}
+
+	tests := map[string]Test{
+		"C from stdin": {
+			args:      []string{"build", "--target=c", "-cell=32", "-signed", "-tape=100", "-eof=zero"},
+			stdin:     "+++,.",
+			expOutput: []string{"typedef int32_t cell_t;", "#define TAPE_SIZE 100", "tape[p] = c == EOF ? 0 : (cell_t)c;"},
+		},
+
+		"not optimized": {
+			args:      []string{"build", "-target", "c", "-O=false"},
+			stdin:     "++",
+			expOutput: []string{"\ttape[p] += 1u;\n\ttape[p] += 1u;\n"},
+		},
+
+		"preprocessed file": {
+			args:      []string{"build", "-target=c", "-preprocess", filepath.Join(dir, "main.b")},
+			expOutput: []string{"\ttape[p] += 3u;\n\tputchar((unsigned char)tape[p]);\n"},
+		},
+
+		"LLVM": {
+			args:      []string{"build", "-target=llvm", "-cell=32", "-tape=64"},
+			stdin:     "+.",
+			expOutput: []string{"@tape = internal global [64 x i32] zeroinitializer\n", "define i32 @main() {\n"},
+		},
+
+		"WebAssembly": {
+			args:      []string{"build", "-target=wasm"},
+			stdin:     "+.",
+			expOutput: []string{"\x00asm\x01\x00\x00\x00"},
+		},
+
+		"WebAssembly text": {
+			args:      []string{"build", "-target=wat", "-cell=16"},
+			stdin:     "+.",
+			expOutput: []string{"(module\n", "i32.load16_u\n"},
+		},
+
+		"unknown target": {
+			args:   []string{"build", "-target=cobol"},
+			expErr: true,
+		},
+
+		"invalid cell width": {
+			args:   []string{"build", "-target=c", "-cell=7"},
+			expErr: true,
+		},
+
+		"unknown EOF policy": {
+			args:   []string{"build", "-target=c", "-eof=never"},
+			expErr: true,
+		},
+
+		"unmatched loop": {
+			args:   []string{"build", "-target=c"},
+			stdin:  "[",
+			expErr: true,
+		},
+
+		"Go": {
+			args:      []string{"gen-go", "-package=kernels", "-func=Kernel", "-cell=16"},
+			stdin:     "+.",
+			expOutput: []string{"package kernels\n", "func Kernel(in brainfuck.InputReader[uint16], out brainfuck.OutputWriter[uint16]) ([]uint16, error) {"},
+		},
+
+		"Go without package": {
+			args:   []string{"gen-go", "-package="},
+			stdin:  "+.",
+			expErr: true,
+		},
+
+		"unknown command": {
+			args:   []string{"launch"},
+			expErr: true,
+		},
+
+		"no command": {
+			expErr: true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout bytes.Buffer
+
+			err := run(test.args, strings.NewReader(test.stdin), &stdout, &bytes.Buffer{})
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			for _, exp := range test.expOutput {
+				require.Contains(t, stdout.String(), exp)
+			}
+		})
+	}
+}
+
+func TestRun_BuildOutputFile(t *testing.T) {
+	t.Parallel()
+
+	outFile := filepath.Join(t.TempDir(), "prog.c")
+
+	err := run([]string{"build", "-target=c", "-o", outFile}, strings.NewReader("+."), &bytes.Buffer{}, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	src, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "int main(void) {")
+}
+
+func TestRun_Run(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		args  []string
+		stdin string
+
+		expErr    bool
+		expOutput string
+		expDump   string
+	}
+
+	tests := map[string]Test{
+		// the program takes stdin, so its input is empty
+		"program from stdin": {
+			args:      []string{"run", "-eof=zero", "-"},
+			stdin:     ",[.,]",
+			expOutput: "",
+		},
+
+		"hex dump": {
+			args:    []string{"run", "-dump=hex", "-tape=4"},
+			stdin:   "+++>++",
+			expDump: "00000000  03 02" + strings.Repeat("   ", 14) + "  |..|\n",
+		},
+
+		"json dump of signed cells": {
+			args:    []string{"run", "-dump=json", "-cell=16", "-signed"},
+			stdin:   "->+",
+			expDump: "[-1,1]\n",
+		},
+
+		"window dump": {
+			args:    []string{"run", "-dump=window", "-tape=3"},
+			stdin:   "+>++",
+			expDump: "[0]  1 2 0\n       ^\n",
+		},
+
+		"debug command": {
+			args:      []string{"run", "-debug=csv", "-tape=3"},
+			stdin:     "++#+.",
+			expOutput: "\x03",
+			expDump:   "#cmd: 2, ptr: 0\nindex,value\n0,2\n",
+		},
+
+		"trace": {
+			args:      []string{"run", "-trace"},
+			stdin:     "+ .",
+			expOutput: "\x01",
+			expDump:   "#cmd: 0 '+', ptr: 0, cell: 0\n#cmd: 2 '.', ptr: 0, cell: 1\n",
+		},
+
+		"unknown dump format": {
+			args:   []string{"run", "-dump=xml"},
+			stdin:  "+",
+			expErr: true,
+		},
+
+		"unsupported cell width": {
+			args:   []string{"run", "-cell=12"},
+			stdin:  "+",
+			expErr: true,
+		},
+
+		"program error": {
+			args:   []string{"run", "-tape=2"},
+			stdin:  ">>",
+			expErr: true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			err := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, stdout.String())
+			require.Equal(t, test.expDump, stderr.String())
+		})
+	}
+}
+
+func TestRun_RunInput(t *testing.T) {
+	t.Parallel()
+
+	prog := filepath.Join(t.TempDir(), "echo.b")
+	require.NoError(t, os.WriteFile(prog, []byte(",[.,]"), 0644))
+
+	var stdout bytes.Buffer
+
+	err := run([]string{"run", "-eof=zero", prog}, strings.NewReader("hello"), &stdout, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Equal(t, "hello", stdout.String())
+}
+
+func TestRun_RunPreprocessed(t *testing.T) {
+	t.Parallel()
+
+	prog := filepath.Join(t.TempDir(), "main.b")
+	require.NoError(t, os.WriteFile(prog, []byte("+\n +."), 0644))
+
+	var stderr bytes.Buffer
+
+	err := run([]string{"run", "-preprocess", "-trace", prog}, strings.NewReader(""), &bytes.Buffer{}, &stderr)
+	require.NoError(t, err)
+	require.Equal(t, "#cmd: 0 (main.b:1:1) '+', ptr: 0, cell: 0\n"+
+		"#cmd: 1 (main.b:2:2) '+', ptr: 0, cell: 1\n"+
+		"#cmd: 2 (main.b:2:3) '.', ptr: 0, cell: 2\n", stderr.String())
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/preprocess"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/tapeview"
+
+	"golang.org/x/exp/constraints"
+)
+
+// runConfig is the interpreter setup of run command
+type runConfig struct {
+	tapeSize  int
+	eofPolicy brainfuck.EOFPolicy
+
+	// dump is the format of the tape that is written to stderr after the run, no dump when it's empty
+	dump tapeview.Format
+
+	// debug enables Dump ('#') command that writes to stderr in debugFormat
+	debug       bool
+	debugFormat tapeview.Format
+
+	// trace writes every command to stderr
+	trace bool
+}
+
+func runProgram(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+
+	cellBits := fs.Int("cell", 8, "cell width in bits: 8, 16, 32 or 64")
+	signed := fs.Bool("signed", false, "signed cells")
+	tapeSize := fs.Int("tape", brainfuck.DefaultDataSize, "tape size in cells")
+	eof := fs.String("eof", brainfuck.EOFError.String(), "EOF policy: error, zero, minus-one or no-change")
+	dump := fs.String("dump", "", "write the tape to stderr after the run: hex, window, json or csv")
+	debug := fs.String("debug", "", "enable '#' command that writes the tape to stderr: hex, window, json or csv")
+	trace := fs.Bool("trace", false, "write every command to stderr")
+	pp := fs.Bool("preprocess", false, "run the preprocessor (includes, macros) on the program file, "+
+		"dumps, traces and errors report positions in its sources")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return err
+	}
+
+	policy, err := brainfuck.ParseEOFPolicy(*eof)
+	if err != nil {
+		return err
+	}
+
+	cfg := runConfig{
+		tapeSize:  *tapeSize,
+		eofPolicy: policy,
+		trace:     *trace,
+	}
+
+	if *dump != "" {
+		if cfg.dump, err = tapeview.ParseFormat(*dump); err != nil {
+			return err
+		}
+	}
+
+	if *debug != "" {
+		cfg.debug = true
+		if cfg.debugFormat, err = tapeview.ParseFormat(*debug); err != nil {
+			return err
+		}
+	}
+
+	// the program is read before it runs, so stdin is left for the program input
+	src, err := readProgram(fs.Arg(0), stdin, *pp)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *cellBits == 8 && !*signed:
+		return interpret[uint8](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 8:
+		return interpret[int8](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 16 && !*signed:
+		return interpret[uint16](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 16:
+		return interpret[int16](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 32 && !*signed:
+		return interpret[uint32](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 32:
+		return interpret[int32](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 64 && !*signed:
+		return interpret[uint64](src, cfg, stdin, stdout, stderr)
+	case *cellBits == 64:
+		return interpret[int64](src, cfg, stdin, stdout, stderr)
+	default:
+		return fmt.Errorf("unsupported cell width: %d", *cellBits)
+	}
+}
+
+// interpret runs a program with the interpreter and dumps the tape if it's requested
+func interpret[DataType constraints.Integer](
+	src *preprocess.Result,
+	cfg runConfig,
+	stdin io.Reader,
+	stdout, stderr io.Writer,
+) error {
+	out := &byteWriter[DataType]{w: bufio.NewWriter(stdout)}
+
+	bf := brainfuck.New[DataType](cfg.tapeSize, reader.BuildStreamReader[DataType](stdin), out).
+		WithEOFPolicy(cfg.eofPolicy).
+		WithoutPrompt()
+
+	if src.Map != nil {
+		bf.WithSourceMap(src.Map)
+	}
+
+	if cfg.debug {
+		brainfuck.EnableDumpCmdFormat(bf, stderr, cfg.debugFormat)
+	}
+
+	if cfg.trace {
+		bf.Use(brainfuck.TraceTo[DataType](stderr))
+	}
+
+	data, runErr := bf.Run(bytes.NewReader(src.Code))
+
+	// the output is flushed even if the program fails, as it's what the program has written
+	if err := out.w.Flush(); err != nil && runErr == nil {
+		runErr = fmt.Errorf("failed to write output: %w", err)
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	if cfg.dump == "" {
+		return nil
+	}
+
+	if err := tapeview.Render(stderr, cfg.dump, data, int(bf.DataPtr)); err != nil {
+		return fmt.Errorf("failed to dump tape: %w", err)
+	}
+
+	return nil
+}
+
+// byteWriter implements brainfuck.OutputWriter. It writes every value as a byte like generated programs do.
+type byteWriter[DataType constraints.Integer] struct {
+	w *bufio.Writer
+}
+
+func (w *byteWriter[DataType]) Write(v DataType) error {
+	return w.w.WriteByte(byte(v))
+}
+
+func (w *byteWriter[DataType]) Close() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,222 @@
+// Package c generates portable C source from a brainfuck program.
+//
+// The program reads input with getchar and writes output with putchar. Errors are reported to stderr
+// in the same format as BfInterpreter.Run reports them, and the program exits with status 1.
+package c
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+)
+
+// Generate writes C source of the program
+func Generate(w io.Writer, prog ir.Program, opts codegen.Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	g := generator{opts: opts}
+
+	g.header(prog)
+
+	g.indent = 1
+	for _, in := range prog {
+		if err := g.instr(in); err != nil {
+			return err
+		}
+	}
+
+	g.line("return 0;")
+	g.indent = 0
+	g.line("}")
+
+	if _, err := w.Write(g.buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write C source: %w", err)
+	}
+
+	return nil
+}
+
+type generator struct {
+	opts   codegen.Options
+	buf    bytes.Buffer
+	indent int
+}
+
+func (g *generator) line(format string, args ...any) {
+	g.buf.WriteString(strings.Repeat("\t", g.indent))
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func (g *generator) header(prog ir.Program) {
+	cellType := fmt.Sprintf("int%d_t", g.opts.CellBits)
+	if !g.opts.Signed {
+		cellType = "u" + cellType
+	}
+
+	g.line("// Code generated by bf build --target=c. DO NOT EDIT.")
+	g.line("")
+	g.line("#include <stdint.h>")
+	g.line("#include <stdio.h>")
+	g.line("#include <stdlib.h>")
+	g.line("")
+	g.line("#define TAPE_SIZE %d", g.opts.TapeSize)
+	g.line("")
+	g.line("typedef %s cell_t;", cellType)
+	g.line("typedef uint%d_t ucell_t;", g.opts.CellBits)
+	g.line("")
+	// unused variables fail builds with -Werror, i.e. when a program only moves the pointer
+	if usesTape(prog) {
+		g.line("static cell_t tape[TAPE_SIZE];")
+		g.line("")
+	}
+
+	if g.canFail(prog) {
+		g.line("static void fail(long cmd, const char *msg) {")
+		g.line("\tfflush(stdout);")
+		g.line("\tfprintf(stderr, \"failed to process [#cmd: %%ld]: %%s\\n\", cmd, msg);")
+		g.line("\texit(1);")
+		g.line("}")
+		g.line("")
+	}
+
+	g.line("int main(void) {")
+
+	if len(prog) > 0 {
+		g.line("\tsize_t p = 0;")
+	}
+
+	if prog.HasInput() {
+		g.line("\tint c;")
+	}
+
+	g.line("")
+}
+
+// usesTape reports whether the program reads or changes cells
+func usesTape(prog ir.Program) bool {
+	for _, in := range prog {
+		if in.Op != ir.OpMove {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canFail reports whether the program may fail on bounds check or at the end of input
+func (g *generator) canFail(prog ir.Program) bool {
+	for _, in := range prog {
+		if in.Op == ir.OpMove && g.opts.BoundsCheck {
+			return true
+		}
+
+		if in.Op == ir.OpIn && g.opts.EOFPolicy == brainfuck.EOFError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *generator) instr(in ir.Instr) error {
+	switch in.Op {
+	case ir.OpAdd:
+		g.add(in.Arg)
+
+	case ir.OpMove:
+		g.move(in)
+
+	case ir.OpOut:
+		g.line("putchar((unsigned char)tape[p]);")
+
+	case ir.OpIn:
+		g.in(in)
+
+	case ir.OpLoop:
+		g.line("while (tape[p]) {")
+		g.indent++
+
+	case ir.OpEnd:
+		g.indent--
+		g.line("}")
+
+	case ir.OpClear:
+		g.line("tape[p] = 0;")
+
+	default:
+		return fmt.Errorf("unknown instruction: %s", in.Op)
+	}
+
+	return nil
+}
+
+func (g *generator) add(n int) {
+	v := g.literal(g.opts.Unsigned(n))
+
+	// signed overflow is undefined in C, so signed cells are changed as unsigned
+	if g.opts.Signed {
+		g.line("tape[p] = (cell_t)((ucell_t)tape[p] + %s);", v)
+		return
+	}
+
+	g.line("tape[p] += %s;", v)
+}
+
+func (g *generator) move(in ir.Instr) {
+	// a folded move fails on the command that reaches the boundary (see ir.Instr)
+	if g.opts.BoundsCheck {
+		switch {
+		case in.Arg == 1:
+			g.line("if (p + 1 >= TAPE_SIZE) fail(%d, \"shift+ moves out of boundary\");", in.Offset)
+		case in.Arg == -1:
+			g.line("if (p < 1) fail(%d, \"shift- moves out of boundary\");", in.Offset)
+		case in.Arg > 0:
+			g.line("if (p + %d >= TAPE_SIZE) fail(%d + (long)(TAPE_SIZE - 1 - p), \"shift+ moves out of boundary\");",
+				in.Arg, in.Offset)
+		default:
+			g.line("if (p < %d) fail(%d + (long)p, \"shift- moves out of boundary\");", -in.Arg, in.Offset)
+		}
+	}
+
+	if in.Arg > 0 {
+		g.line("p += %d;", in.Arg)
+	} else {
+		g.line("p -= %d;", -in.Arg)
+	}
+}
+
+func (g *generator) in(in ir.Instr) {
+	g.line("c = getchar();")
+
+	switch g.opts.EOFPolicy {
+	case brainfuck.EOFError:
+		g.line("if (c == EOF) fail(%d, \"failed to read value: EOF\");", in.Offset)
+		g.line("tape[p] = (cell_t)c;")
+
+	case brainfuck.EOFZero:
+		g.line("tape[p] = c == EOF ? 0 : (cell_t)c;")
+
+	case brainfuck.EOFMinusOne:
+		g.line("tape[p] = c == EOF ? (cell_t)-1 : (cell_t)c;")
+
+	case brainfuck.EOFNoChange:
+		g.line("if (c != EOF) tape[p] = (cell_t)c;")
+	}
+}
+
+// literal returns an unsigned C literal
+func (g *generator) literal(v uint64) string {
+	if g.opts.CellBits == 64 {
+		return fmt.Sprintf("%dull", v)
+	}
+
+	return fmt.Sprintf("%du", v)
+}
@@ -0,0 +1,197 @@
+package c
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+const helloWorld = `++++++++[>++++[>++>+++>+++>+<<<<-]>+>+>->>+[<]<-]>>.>---.+++++++..+++.>>.<-.<.+++.------.--------.>>+.>++.`
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	prog, err := ir.ParseString(`+++[>,.<-]`)
+	require.NoError(t, err)
+
+	opts := codegen.DefaultOptions()
+	opts.CellBits = 16
+	opts.Signed = true
+	opts.TapeSize = 10
+	opts.EOFPolicy = brainfuck.EOFZero
+
+	var src bytes.Buffer
+	require.NoError(t, Generate(&src, ir.Optimize(prog), opts))
+
+	for _, line := range []string{
+		"#define TAPE_SIZE 10",
+		"typedef int16_t cell_t;",
+		"typedef uint16_t ucell_t;",
+		"\tint c;",
+		"\ttape[p] = (cell_t)((ucell_t)tape[p] + 3u);",
+		"\twhile (tape[p]) {",
+		"\t\tif (p + 1 >= TAPE_SIZE) fail(4, \"shift+ moves out of boundary\");",
+		"\t\ttape[p] = c == EOF ? 0 : (cell_t)c;",
+		"\t\ttape[p] = (cell_t)((ucell_t)tape[p] + 65535u);",
+	} {
+		require.Contains(t, src.String(), line+"\n")
+	}
+}
+
+func TestGenerate_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := codegen.DefaultOptions()
+	opts.CellBits = 12
+
+	require.Error(t, Generate(&bytes.Buffer{}, nil, opts))
+}
+
+// TestGenerate_Differential compiles generated programs and compares their behaviour with BfInterpreter
+func TestGenerate_Differential(t *testing.T) {
+	t.Parallel()
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("C compiler is not found")
+	}
+
+	type Test struct {
+		code   string
+		input  string
+		policy brainfuck.EOFPolicy
+	}
+
+	tests := map[string]Test{
+		"hello world": {
+			code: helloWorld,
+		},
+
+		"echo": {
+			code:   `,[.,]`,
+			input:  "echo me",
+			policy: brainfuck.EOFZero,
+		},
+
+		"cell wraps": {
+			code: `-.+.[-]+++[>+++++<-]>.`,
+		},
+
+		"EOF minus one": {
+			code:   `,.`,
+			policy: brainfuck.EOFMinusOne,
+		},
+
+		"EOF no change": {
+			code:   `++,.`,
+			policy: brainfuck.EOFNoChange,
+		},
+
+		"EOF error": {
+			code:  `,.,.`,
+			input: "a",
+		},
+
+		"out of boundary": {
+			code: `+.<`,
+		},
+
+		"out of boundary right": {
+			code: `>>+>>.>`,
+		},
+
+		"cancelled moves at left edge": {
+			code: `<>+.`,
+		},
+
+		"cancelled moves": {
+			code: `><+.`,
+		},
+
+		"moves back": {
+			code: `>>><<+.`,
+		},
+
+		"folded moves out of boundary right": {
+			code: `+.>>>>>>`,
+		},
+
+		"folded moves out of boundary left": {
+			code: `>><<<`,
+		},
+
+		"separated moves out of boundary": {
+			code: `>> >>>`,
+		},
+
+		"empty program": {},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			// interpreter
+			output := writer.BuildSliceWriter[uint8]()
+			bf := brainfuck.New[uint8](5, reader.BuildStringReader[uint8](test.input), output).
+				WithoutPrompt().
+				WithEOFPolicy(test.policy)
+
+			_, runErr := bf.Run(strings.NewReader(test.code))
+
+			// compiled program
+			prog, err := ir.ParseString(test.code)
+			require.NoError(t, err)
+
+			opts := codegen.DefaultOptions()
+			opts.TapeSize = 5
+			opts.EOFPolicy = test.policy
+
+			dir := t.TempDir()
+			srcFile := filepath.Join(dir, "prog.c")
+			binFile := filepath.Join(dir, "prog")
+
+			var src bytes.Buffer
+			require.NoError(t, Generate(&src, ir.Optimize(prog), opts))
+			require.NoError(t, os.WriteFile(srcFile, src.Bytes(), 0644))
+
+			out, err := exec.Command(cc, "-std=c99", "-Wall", "-Werror", "-o", binFile, srcFile).CombinedOutput()
+			require.NoError(t, err, string(out))
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binFile)
+			cmd.Stdin = strings.NewReader(test.input)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err = cmd.Run()
+
+			require.Equal(t, output.String(), stdout.String())
+
+			if runErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var exitErr *exec.ExitError
+			require.True(t, errors.As(err, &exitErr))
+			require.Equal(t, 1, exitErr.ExitCode())
+			require.Equal(t, runErr.Error()+"\n", stderr.String())
+		})
+	}
+}
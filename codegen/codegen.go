@@ -0,0 +1,70 @@
+// Package codegen contains settings that are shared by code generators (codegen/c, ...).
+// Generated programs behave like BfInterpreter configured with the same cell type, tape size and EOF policy.
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+)
+
+// Options defines the machine that generated code implements
+type Options struct {
+
+	// CellBits is a cell width: 8, 16, 32 or 64
+	CellBits int
+
+	// Signed makes cells signed integers
+	Signed bool
+
+	// TapeSize is the number of cells
+	TapeSize int
+
+	// EOFPolicy defines what In (',') command does at the end of input
+	EOFPolicy brainfuck.EOFPolicy
+
+	// BoundsCheck makes a program fail when the data pointer moves out of the tape like BfInterpreter does.
+	// Without it moving out of the tape is undefined behaviour.
+	BoundsCheck bool
+}
+
+// DefaultOptions returns options of the most common brainfuck machine: unsigned 8-bit cells with bounds checks
+func DefaultOptions() Options {
+	return Options{
+		CellBits:    8,
+		TapeSize:    brainfuck.DefaultDataSize,
+		EOFPolicy:   brainfuck.EOFError,
+		BoundsCheck: true,
+	}
+}
+
+// Validate checks options
+func (o Options) Validate() error {
+	switch o.CellBits {
+	case 8, 16, 32, 64:
+	default:
+		return fmt.Errorf("unsupported cell width: %d", o.CellBits)
+	}
+
+	if o.TapeSize <= 0 {
+		return fmt.Errorf("invalid tape size: %d", o.TapeSize)
+	}
+
+	switch o.EOFPolicy {
+	case brainfuck.EOFError, brainfuck.EOFZero, brainfuck.EOFMinusOne, brainfuck.EOFNoChange:
+	default:
+		return fmt.Errorf("unknown EOF policy: %d", o.EOFPolicy)
+	}
+
+	return nil
+}
+
+// Unsigned returns n modulo 2^CellBits, i.e. the value that is added to a cell to add n
+func (o Options) Unsigned(n int) uint64 {
+	v := uint64(int64(n))
+	if o.CellBits < 64 {
+		v &= 1<<o.CellBits - 1
+	}
+
+	return v
+}
@@ -0,0 +1,311 @@
+// Package golang generates Go source from a brainfuck program.
+//
+// The generated function has the signature
+//
+//	func Run(in brainfuck.InputReader[T], out brainfuck.OutputWriter[T]) ([]T, error)
+//
+// where T is the cell type, and it behaves like BfInterpreter.Run with the same settings:
+// it passes the same hints to the reader, returns the tape on success and *brainfuck.CmdError on failure.
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+)
+
+const pkgPath = "github.com/yurii-vyrovyi/brainfuck"
+
+// Config defines the generated Go code
+type Config struct {
+
+	// Package is the package name of the generated file
+	Package string
+
+	// Func is the name of the generated function. It's "Run" by default.
+	Func string
+
+	// NoPrompt makes the function pass hints without text to the reader like WithoutPrompt does.
+	// Otherwise, hints have brainfuck.DefaultPrompt text.
+	NoPrompt bool
+}
+
+// Generate writes gofmt'ed Go source of the program
+func Generate(w io.Writer, prog ir.Program, opts codegen.Options, cfg Config) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Package == "" {
+		return fmt.Errorf("package name expected")
+	}
+
+	if cfg.Func == "" {
+		cfg.Func = "Run"
+	}
+
+	g := generator{
+		opts:     opts,
+		cfg:      cfg,
+		cellType: CellType(opts),
+		imports:  map[string]bool{pkgPath: true},
+	}
+
+	if err := g.body(prog); err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	g.header(&src)
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format Go source: %w", err)
+	}
+
+	if _, err := w.Write(formatted); err != nil {
+		return fmt.Errorf("failed to write Go source: %w", err)
+	}
+
+	return nil
+}
+
+// CellType returns Go type of cells, i.e. uint8
+func CellType(opts codegen.Options) string {
+	if opts.Signed {
+		return fmt.Sprintf("int%d", opts.CellBits)
+	}
+
+	return fmt.Sprintf("uint%d", opts.CellBits)
+}
+
+type generator struct {
+	opts     codegen.Options
+	cfg      Config
+	cellType string
+
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) line(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func (g *generator) header(w io.Writer) {
+	fmt.Fprintf(w, "// Code generated by bf gen-go. DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.cfg.Package)
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+
+	// standard library first
+	for _, imp := range imports {
+		if !strings.Contains(imp, ".") {
+			fmt.Fprintf(w, "%q\n", imp)
+		}
+	}
+
+	w.Write([]byte("\n"))
+
+	for _, imp := range imports {
+		if strings.Contains(imp, ".") {
+			fmt.Fprintf(w, "%q\n", imp)
+		}
+	}
+
+	w.Write([]byte(")\n\n"))
+}
+
+func (g *generator) body(prog ir.Program) error {
+	t := g.cellType
+
+	g.line("// %s runs the brainfuck program with %d cells of %s type.", g.cfg.Func, g.opts.TapeSize, t)
+	g.line("func %s(in brainfuck.InputReader[%s], out brainfuck.OutputWriter[%s]) ([]%s, error) {", g.cfg.Func, t, t, t)
+	g.line("data := make([]%s, %d)", t, g.opts.TapeSize)
+
+	if len(prog) > 0 {
+		g.line("p := 0")
+	}
+
+	if prog.HasInput() {
+		g.line("")
+		g.read()
+	}
+
+	if prog.HasOutput() {
+		g.line("")
+		g.write()
+	}
+
+	g.line("")
+
+	for _, in := range prog {
+		if err := g.instr(in); err != nil {
+			return err
+		}
+	}
+
+	g.line("")
+	g.line("return data, nil")
+	g.line("}")
+
+	return nil
+}
+
+// read declares a closure that implements In command
+func (g *generator) read() {
+	g.imports["github.com/yurii-vyrovyi/brainfuck/prompt"] = true
+	g.imports["fmt"] = true
+
+	g.line("read := func(cmdPtr brainfuck.CmdPtrType) error {")
+	g.line("v, err := in.Read(prompt.Hint[%s]{", g.cellType)
+
+	if !g.cfg.NoPrompt {
+		g.line("Text: brainfuck.DefaultPrompt(cmdPtr, brainfuck.DataPtrType(p), data[p]),")
+	}
+
+	g.line("CmdPtr: int(cmdPtr),")
+	g.line("DataPtr: p,")
+	g.line("Value: data[p],")
+	g.line("})")
+
+	if g.opts.EOFPolicy != brainfuck.EOFError {
+		g.imports["errors"] = true
+		g.imports["io"] = true
+
+		g.line("if errors.Is(err, io.EOF) {")
+
+		switch g.opts.EOFPolicy {
+		case brainfuck.EOFZero:
+			g.line("data[p] = 0")
+		case brainfuck.EOFMinusOne:
+			g.line("data[p] = ^%s(0)", g.cellType)
+		}
+
+		g.line("return nil")
+		g.line("}")
+	}
+
+	g.line("if err != nil {")
+	g.line("return &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: ',', Err: fmt.Errorf(\"failed to read value: %%w\", err)}")
+	g.line("}")
+	g.line("data[p] = v")
+	g.line("return nil")
+	g.line("}")
+}
+
+// write declares a closure that implements Out command
+func (g *generator) write() {
+	g.imports["fmt"] = true
+
+	g.line("write := func(cmdPtr brainfuck.CmdPtrType) error {")
+	g.line("if err := out.Write(data[p]); err != nil {")
+	g.line("return &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '.', Err: fmt.Errorf(\"failed to write value: %%w\", err)}")
+	g.line("}")
+	g.line("return nil")
+	g.line("}")
+}
+
+func (g *generator) instr(in ir.Instr) error {
+	switch in.Op {
+	case ir.OpAdd:
+		switch v := g.constant(in.Arg); v {
+		case "1":
+			g.line("data[p]++")
+		case "-1", g.constant(-1):
+			g.line("data[p]--")
+		default:
+			g.line("data[p] += %s", v)
+		}
+
+	case ir.OpMove:
+		g.move(in)
+
+	case ir.OpOut:
+		g.line("if err := write(%d); err != nil {", in.Offset)
+		g.line("return nil, err")
+		g.line("}")
+
+	case ir.OpIn:
+		g.line("if err := read(%d); err != nil {", in.Offset)
+		g.line("return nil, err")
+		g.line("}")
+
+	case ir.OpLoop:
+		g.line("for data[p] != 0 {")
+
+	case ir.OpEnd:
+		g.line("}")
+
+	case ir.OpClear:
+		g.line("data[p] = 0")
+
+	default:
+		return fmt.Errorf("unknown instruction: %s", in.Op)
+	}
+
+	return nil
+}
+
+func (g *generator) move(in ir.Instr) {
+	if g.opts.BoundsCheck {
+		g.imports["errors"] = true
+
+		// a folded move fails on the command that reaches the boundary (see ir.Instr)
+		switch {
+		case in.Arg == 1:
+			g.line("if p+1 >= len(data) {")
+			g.line("return nil, &brainfuck.CmdError{CmdPtr: %d, Cmd: '>', Err: errors.New(\"shift+ moves out of boundary\")}", in.Offset)
+		case in.Arg == -1:
+			g.line("if p < 1 {")
+			g.line("return nil, &brainfuck.CmdError{CmdPtr: %d, Cmd: '<', Err: errors.New(\"shift- moves out of boundary\")}", in.Offset)
+		case in.Arg > 0:
+			g.line("if p+%d >= len(data) {", in.Arg)
+			g.line("return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(%d + len(data) - 1 - p), Cmd: '>', Err: errors.New(\"shift+ moves out of boundary\")}", in.Offset)
+		default:
+			g.line("if p < %d {", -in.Arg)
+			g.line("return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(%d + p), Cmd: '<', Err: errors.New(\"shift- moves out of boundary\")}", in.Offset)
+		}
+
+		g.line("}")
+	}
+
+	switch {
+	case in.Arg == 1:
+		g.line("p++")
+	case in.Arg == -1:
+		g.line("p--")
+	case in.Arg > 0:
+		g.line("p += %d", in.Arg)
+	default:
+		g.line("p -= %d", -in.Arg)
+	}
+}
+
+// constant returns n modulo 2^CellBits as a constant that fits the cell type
+func (g *generator) constant(n int) string {
+	v := g.opts.Unsigned(n)
+
+	if !g.opts.Signed {
+		return fmt.Sprintf("%d", v)
+	}
+
+	// values of the upper half are negative
+	if g.opts.CellBits < 64 && v >= 1<<(g.opts.CellBits-1) {
+		return fmt.Sprintf("%d", int64(v)-1<<g.opts.CellBits)
+	}
+
+	return fmt.Sprintf("%d", int64(v))
+}
@@ -0,0 +1,141 @@
+package golang
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		code string
+		opts func(o *codegen.Options)
+		cfg  Config
+
+		expErr      bool
+		expContains []string
+		expImports  []string
+	}
+
+	tests := map[string]Test{
+		"defaults": {
+			code: `+++[>,.<-]`,
+			cfg:  Config{Package: "kernels"},
+			expContains: []string{
+				"func Run(in brainfuck.InputReader[uint8], out brainfuck.OutputWriter[uint8]) ([]uint8, error) {",
+				"data := make([]uint8, 4096)",
+				"Text:    brainfuck.DefaultPrompt(cmdPtr, brainfuck.DataPtrType(p), data[p]),",
+				"data[p] += 3\n",
+				"data[p]--\n",
+				"p++\n",
+			},
+			expImports: []string{`"fmt"`, `"errors"`, `"github.com/yurii-vyrovyi/brainfuck/prompt"`},
+		},
+
+		"signed cells": {
+			code: strings.Repeat("+", 200),
+			opts: func(o *codegen.Options) {
+				o.Signed = true
+				o.BoundsCheck = false
+			},
+			cfg:         Config{Package: "kernels", Func: "Kernel"},
+			expContains: []string{"func Kernel(", "[]int8", "data[p] += -56\n"},
+		},
+
+		"EOF minus one without prompt": {
+			code: `,`,
+			opts: func(o *codegen.Options) {
+				o.CellBits = 64
+				o.EOFPolicy = brainfuck.EOFMinusOne
+			},
+			cfg:         Config{Package: "kernels", NoPrompt: true},
+			expContains: []string{"data[p] = ^uint64(0)\n", "if errors.Is(err, io.EOF) {"},
+			expImports:  []string{`"io"`},
+		},
+
+		"folded moves": {
+			code: `>>><<`,
+			cfg:  Config{Package: "kernels"},
+			expContains: []string{
+				"CmdPtr: brainfuck.CmdPtrType(0 + len(data) - 1 - p), Cmd: '>'",
+				"CmdPtr: brainfuck.CmdPtrType(3 + p), Cmd: '<'",
+			},
+		},
+
+		"empty program": {
+			cfg:         Config{Package: "kernels"},
+			expContains: []string{"return data, nil"},
+		},
+
+		"no package": {
+			code:   `+`,
+			expErr: true,
+		},
+
+		"invalid options": {
+			code:   `+`,
+			opts:   func(o *codegen.Options) { o.TapeSize = 0 },
+			cfg:    Config{Package: "kernels"},
+			expErr: true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			prog, err := ir.ParseString(test.code)
+			require.NoError(t, err)
+
+			opts := codegen.DefaultOptions()
+			if test.opts != nil {
+				test.opts(&opts)
+			}
+
+			var src bytes.Buffer
+			err = Generate(&src, ir.Optimize(prog), opts, test.cfg)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// output is gofmt'ed
+			formatted, err := format.Source(src.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, string(formatted), src.String())
+
+			f, err := parser.ParseFile(token.NewFileSet(), "kernel.go", src.Bytes(), parser.ImportsOnly)
+			require.NoError(t, err)
+			require.Equal(t, test.cfg.Package, f.Name.Name)
+
+			imports := make([]string, 0, len(f.Imports))
+			for _, imp := range f.Imports {
+				imports = append(imports, imp.Path.Value)
+			}
+			require.Contains(t, imports, `"github.com/yurii-vyrovyi/brainfuck"`)
+
+			for _, imp := range test.expImports {
+				require.Contains(t, imports, imp)
+			}
+
+			for _, s := range test.expContains {
+				require.Contains(t, src.String(), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Package llvm generates textual LLVM IR from a brainfuck program.
+//
+// The module defines main, uses getchar and putchar for input and output and reports errors to stderr
+// in the same format as BfInterpreter.Run does, exiting with status 1. IR uses opaque pointers (LLVM 15+).
+package llvm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+)
+
+// messages are errors that programs report, the same as BfInterpreter reports
+var messages = []struct {
+	name string
+	text string
+}{
+	{name: "shift_right", text: "shift+ moves out of boundary"},
+	{name: "shift_left", text: "shift- moves out of boundary"},
+	{name: "eof", text: "failed to read value: EOF"},
+}
+
+const errorFormat = "failed to process [#cmd: %lld]: %s\n"
+
+// Generate writes LLVM IR of the program
+func Generate(w io.Writer, prog ir.Program, opts codegen.Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	g := generator{
+		opts:     opts,
+		cell:     fmt.Sprintf("i%d", opts.CellBits),
+		tapeType: fmt.Sprintf("[%d x i%d]", opts.TapeSize, opts.CellBits),
+	}
+
+	g.header(prog)
+
+	g.line("define i32 @main() {")
+	g.line("entry:")
+	g.line("  %%p = alloca i64")
+	g.line("  store i64 0, ptr %%p")
+
+	for i, in := range prog {
+		if err := g.instr(i, in); err != nil {
+			return err
+		}
+	}
+
+	g.line("  ret i32 0")
+	g.line("}")
+
+	if _, err := w.Write(g.buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write LLVM IR: %w", err)
+	}
+
+	return nil
+}
+
+type generator struct {
+	opts     codegen.Options
+	buf      bytes.Buffer
+	cell     string
+	tapeType string
+
+	// tmp is the number of the last temporary value
+	tmp int
+}
+
+func (g *generator) line(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// next returns a new temporary value name
+func (g *generator) next() string {
+	g.tmp++
+	return fmt.Sprintf("%%t%d", g.tmp)
+}
+
+func (g *generator) header(prog ir.Program) {
+	g.line("; Code generated by bf build --target=llvm. DO NOT EDIT.")
+	g.line("")
+	g.line("@tape = internal global %s zeroinitializer", g.tapeType)
+
+	canFail := g.canFail(prog)
+
+	if canFail {
+		g.line("@fmt = private unnamed_addr constant %s", cString(errorFormat))
+		for _, m := range messages {
+			g.line("@msg.%s = private unnamed_addr constant %s", m.name, cString(m.text))
+		}
+	}
+
+	g.line("")
+
+	declared := false
+
+	if prog.HasInput() {
+		g.line("declare i32 @getchar()")
+		declared = true
+	}
+
+	if prog.HasOutput() {
+		g.line("declare i32 @putchar(i32)")
+		declared = true
+	}
+
+	if canFail {
+		g.line("declare i32 @fflush(ptr)")
+		g.line("declare i32 @dprintf(i32, ptr, ...)")
+		g.line("declare void @exit(i32) noreturn")
+		g.line("")
+		g.line("define internal void @fail(i64 %%cmd, ptr %%msg) noreturn {")
+		g.line("entry:")
+		g.line("  %%t1 = call i32 @fflush(ptr null)")
+		g.line("  %%t2 = call i32 (i32, ptr, ...) @dprintf(i32 2, ptr @fmt, i64 %%cmd, ptr %%msg)")
+		g.line("  call void @exit(i32 1)")
+		g.line("  unreachable")
+		g.line("}")
+		declared = true
+	}
+
+	if declared {
+		g.line("")
+	}
+}
+
+// canFail reports whether the program may fail on bounds check or at the end of input
+func (g *generator) canFail(prog ir.Program) bool {
+	for _, in := range prog {
+		if in.Op == ir.OpMove && g.opts.BoundsCheck {
+			return true
+		}
+
+		if in.Op == ir.OpIn && g.opts.EOFPolicy == brainfuck.EOFError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cellAddr returns the address of the current cell
+func (g *generator) cellAddr() string {
+	p := g.next()
+	g.line("  %s = load i64, ptr %%p", p)
+
+	addr := g.next()
+	g.line("  %s = getelementptr inbounds %s, ptr @tape, i64 0, i64 %s", addr, g.tapeType, p)
+
+	return addr
+}
+
+// loadCell returns the address and the value of the current cell
+func (g *generator) loadCell() (string, string) {
+	addr := g.cellAddr()
+
+	v := g.next()
+	g.line("  %s = load %s, ptr %s", v, g.cell, addr)
+
+	return addr, v
+}
+
+func (g *generator) instr(i int, in ir.Instr) error {
+	switch in.Op {
+	case ir.OpAdd:
+		addr, v := g.loadCell()
+		res := g.next()
+		g.line("  %s = add %s %s, %d", res, g.cell, v, g.constant(in.Arg))
+		g.line("  store %s %s, ptr %s", g.cell, res, addr)
+
+	case ir.OpMove:
+		g.move(i, in)
+
+	case ir.OpOut:
+		_, v := g.loadCell()
+
+		c := v
+		switch {
+		case g.opts.CellBits < 32:
+			c = g.next()
+			g.line("  %s = zext %s %s to i32", c, g.cell, v)
+		case g.opts.CellBits > 32:
+			c = g.next()
+			g.line("  %s = trunc %s %s to i32", c, g.cell, v)
+		}
+
+		g.line("  %s = call i32 @putchar(i32 %s)", g.next(), c)
+
+	case ir.OpIn:
+		g.in(i, in)
+
+	case ir.OpLoop:
+		g.line("  br label %%loop%d", i)
+		g.line("loop%d:", i)
+
+		_, v := g.loadCell()
+		cond := g.next()
+		g.line("  %s = icmp ne %s %s, 0", cond, g.cell, v)
+		g.line("  br i1 %s, label %%body%d, label %%end%d", cond, i, in.Arg)
+		g.line("body%d:", i)
+
+	case ir.OpEnd:
+		g.line("  br label %%loop%d", in.Arg)
+		g.line("end%d:", i)
+
+	case ir.OpClear:
+		addr := g.cellAddr()
+		g.line("  store %s 0, ptr %s", g.cell, addr)
+
+	default:
+		return fmt.Errorf("unknown instruction: %s", in.Op)
+	}
+
+	return nil
+}
+
+func (g *generator) move(i int, in ir.Instr) {
+	p := g.next()
+	g.line("  %s = load i64, ptr %%p", p)
+
+	res := g.next()
+	g.line("  %s = add i64 %s, %d", res, p, in.Arg)
+
+	if g.opts.BoundsCheck {
+		cond := g.next()
+		msg := "shift_right"
+
+		if in.Arg > 0 {
+			g.line("  %s = icmp uge i64 %s, %d", cond, res, g.opts.TapeSize)
+		} else {
+			msg = "shift_left"
+			g.line("  %s = icmp ult i64 %s, %d", cond, p, -in.Arg)
+		}
+
+		g.line("  br i1 %s, label %%fail%d, label %%move%d", cond, i, i)
+		g.line("fail%d:", i)
+
+		// a folded move fails on the command that reaches the boundary (see ir.Instr)
+		cmd := fmt.Sprint(in.Offset)
+
+		switch {
+		case in.Arg > 1:
+			cmd = g.next()
+			g.line("  %s = sub i64 %d, %s", cmd, in.Offset+g.opts.TapeSize-1, p)
+		case in.Arg < -1:
+			cmd = g.next()
+			g.line("  %s = add i64 %s, %d", cmd, p, in.Offset)
+		}
+
+		g.line("  call void @fail(i64 %s, ptr @msg.%s)", cmd, msg)
+		g.line("  unreachable")
+		g.line("move%d:", i)
+	}
+
+	g.line("  store i64 %s, ptr %%p", res)
+}
+
+func (g *generator) in(i int, in ir.Instr) {
+	c := g.next()
+	g.line("  %s = call i32 @getchar()", c)
+
+	eof := g.next()
+	g.line("  %s = icmp eq i32 %s, -1", eof, c)
+	g.line("  br i1 %s, label %%eof%d, label %%read%d", eof, i, i)
+
+	g.line("eof%d:", i)
+
+	switch g.opts.EOFPolicy {
+	case brainfuck.EOFError:
+		g.line("  call void @fail(i64 %d, ptr @msg.eof)", in.Offset)
+		g.line("  unreachable")
+
+	case brainfuck.EOFZero:
+		addr := g.cellAddr()
+		g.line("  store %s 0, ptr %s", g.cell, addr)
+		g.line("  br label %%next%d", i)
+
+	case brainfuck.EOFMinusOne:
+		addr := g.cellAddr()
+		g.line("  store %s -1, ptr %s", g.cell, addr)
+		g.line("  br label %%next%d", i)
+
+	case brainfuck.EOFNoChange:
+		g.line("  br label %%next%d", i)
+	}
+
+	g.line("read%d:", i)
+
+	v := c
+	switch {
+	case g.opts.CellBits < 32:
+		v = g.next()
+		g.line("  %s = trunc i32 %s to %s", v, c, g.cell)
+	case g.opts.CellBits > 32:
+		v = g.next()
+		g.line("  %s = zext i32 %s to %s", v, c, g.cell)
+	}
+
+	addr := g.cellAddr()
+	g.line("  store %s %s, ptr %s", g.cell, v, addr)
+	g.line("  br label %%next%d", i)
+	g.line("next%d:", i)
+}
+
+// constant returns n modulo 2^CellBits as a signed constant
+func (g *generator) constant(n int) int64 {
+	v := g.opts.Unsigned(n)
+	if g.opts.CellBits < 64 && v >= 1<<(g.opts.CellBits-1) {
+		return int64(v) - 1<<g.opts.CellBits
+	}
+
+	return int64(v)
+}
+
+// cString returns LLVM array constant of a NUL-terminated string
+func cString(s string) string {
+	var sb bytes.Buffer
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x7F || c == '"' || c == '\\' {
+			fmt.Fprintf(&sb, "\\%02X", c)
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return fmt.Sprintf("[%d x i8] c\"%s\\00\"", len(s)+1, sb.String())
+}
@@ -0,0 +1,307 @@
+package llvm
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+const helloWorld = `++++++++[>++++[>++>+++>+++>+<<<<-]>+>+>->>+[<]<-]>>.>---.+++++++..+++.>>.<-.<.+++.------.--------.>>+.>++.`
+
+func TestGenerate_Golden(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		code string
+		opts func(o *codegen.Options)
+	}
+
+	tests := map[string]Test{
+		"hello": {
+			code: helloWorld,
+		},
+
+		"echo_i32": {
+			code: `,[.,]`,
+			opts: func(o *codegen.Options) {
+				o.CellBits = 32
+				o.Signed = true
+				o.TapeSize = 16
+				o.EOFPolicy = brainfuck.EOFZero
+			},
+		},
+
+		"clear_i16_unchecked": {
+			code: `+++[>++<-]>[-]<<`,
+			opts: func(o *codegen.Options) {
+				o.CellBits = 16
+				o.TapeSize = 100
+				o.BoundsCheck = false
+			},
+		},
+
+		"input_i64": {
+			code: `,.,.`,
+			opts: func(o *codegen.Options) {
+				o.CellBits = 64
+				o.EOFPolicy = brainfuck.EOFMinusOne
+			},
+		},
+	}
+
+	//nolint:paralleltest
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prog, err := ir.ParseString(test.code)
+			require.NoError(t, err)
+
+			opts := codegen.DefaultOptions()
+			if test.opts != nil {
+				test.opts(&opts)
+			}
+
+			var src bytes.Buffer
+			require.NoError(t, Generate(&src, ir.Optimize(prog), opts))
+
+			golden := filepath.Join("testdata", name+".ll")
+
+			if *update {
+				require.NoError(t, os.WriteFile(golden, src.Bytes(), 0644))
+			}
+
+			exp, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(exp), src.String())
+
+			verify(t, src.String())
+		})
+	}
+}
+
+// verify checks that every value is defined once and every branch target exists.
+// When LLVM is installed the IR is also checked with llvm-as.
+func verify(t *testing.T, src string) {
+	t.Helper()
+
+	for _, fn := range strings.Split(src, "\ndefine ")[1:] {
+		values := map[string]bool{}
+		for _, m := range regexp.MustCompile(`(?m)^\s+(%[\w.]+) = `).FindAllStringSubmatch(fn, -1) {
+			require.False(t, values[m[1]], "value %s is defined twice", m[1])
+			values[m[1]] = true
+		}
+
+		labels := map[string]bool{}
+		for _, m := range regexp.MustCompile(`(?m)^([\w.]+):$`).FindAllStringSubmatch(fn, -1) {
+			require.False(t, labels[m[1]], "label %s is defined twice", m[1])
+			labels[m[1]] = true
+		}
+
+		for _, m := range regexp.MustCompile(`label %([\w.]+)`).FindAllStringSubmatch(fn, -1) {
+			require.True(t, labels[m[1]], "label %s is not defined", m[1])
+		}
+	}
+
+	llvmAs, err := exec.LookPath("llvm-as")
+	if err != nil {
+		return
+	}
+
+	cmd := exec.Command(llvmAs, append(llvmFlags(llvmAs), "-o", os.DevNull)...)
+	cmd.Stdin = strings.NewReader(src)
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// llvmFlags returns flags of an LLVM tool that enable opaque pointers, they are default since LLVM 15.
+// No flags are returned if the version of the tool is unknown.
+func llvmFlags(tool string) []string {
+	out, err := exec.Command(tool, "--version").Output()
+	if err != nil {
+		return nil
+	}
+
+	m := regexp.MustCompile(`LLVM version (\d+)`).FindSubmatch(out)
+	if m == nil {
+		return nil
+	}
+
+	if v, err := strconv.Atoi(string(m[1])); err == nil && v < 15 {
+		return []string{"-opaque-pointers"}
+	}
+
+	return nil
+}
+
+// TestGenerate_Differential compiles generated IR and compares its behaviour with BfInterpreter
+func TestGenerate_Differential(t *testing.T) {
+	t.Parallel()
+
+	llc, err := exec.LookPath("llc")
+	if err != nil {
+		t.Skip("llc is not found")
+	}
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("C compiler is not found")
+	}
+
+	type Test struct {
+		code   string
+		input  string
+		policy brainfuck.EOFPolicy
+	}
+
+	tests := map[string]Test{
+		"hello world": {
+			code: helloWorld,
+		},
+
+		"echo": {
+			code:   `,[.,]`,
+			input:  "echo me",
+			policy: brainfuck.EOFZero,
+		},
+
+		"cell wraps": {
+			code: `-.+.[-]+++[>+++++<-]>.`,
+		},
+
+		"EOF minus one": {
+			code:   `,.`,
+			policy: brainfuck.EOFMinusOne,
+		},
+
+		"EOF no change": {
+			code:   `++,.`,
+			policy: brainfuck.EOFNoChange,
+		},
+
+		"EOF error": {
+			code:  `,.,.`,
+			input: "a",
+		},
+
+		"out of boundary": {
+			code: `+.<`,
+		},
+
+		"out of boundary right": {
+			code: `>>+>>.>`,
+		},
+
+		"cancelled moves at left edge": {
+			code: `<>+.`,
+		},
+
+		"cancelled moves": {
+			code: `><+.`,
+		},
+
+		"moves back": {
+			code: `>>><<+.`,
+		},
+
+		"folded moves out of boundary right": {
+			code: `+.>>>>>>`,
+		},
+
+		"folded moves out of boundary left": {
+			code: `>><<<`,
+		},
+
+		"separated moves out of boundary": {
+			code: `>> >>>`,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[uint8]()
+			bf := brainfuck.New[uint8](5, reader.BuildStringReader[uint8](test.input), output).
+				WithoutPrompt().
+				WithEOFPolicy(test.policy)
+
+			_, runErr := bf.Run(strings.NewReader(test.code))
+
+			prog, err := ir.ParseString(test.code)
+			require.NoError(t, err)
+
+			opts := codegen.DefaultOptions()
+			opts.TapeSize = 5
+			opts.EOFPolicy = test.policy
+
+			dir := t.TempDir()
+			irFile := filepath.Join(dir, "prog.ll")
+			asmFile := filepath.Join(dir, "prog.s")
+			binFile := filepath.Join(dir, "prog")
+
+			var src bytes.Buffer
+			require.NoError(t, Generate(&src, ir.Optimize(prog), opts))
+			require.NoError(t, os.WriteFile(irFile, src.Bytes(), 0644))
+
+			args := append(llvmFlags(llc), "-relocation-model=pic", "-o", asmFile, irFile)
+			out, err := exec.Command(llc, args...).CombinedOutput()
+			require.NoError(t, err, string(out))
+
+			out, err = exec.Command(cc, "-o", binFile, asmFile).CombinedOutput()
+			require.NoError(t, err, string(out))
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binFile)
+			cmd.Stdin = strings.NewReader(test.input)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err = cmd.Run()
+
+			require.Equal(t, output.String(), stdout.String())
+
+			if runErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var exitErr *exec.ExitError
+			require.True(t, errors.As(err, &exitErr))
+			require.Equal(t, 1, exitErr.ExitCode())
+			require.Equal(t, runErr.Error()+"\n", stderr.String())
+		})
+	}
+}
+
+func TestGenerate_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := codegen.DefaultOptions()
+	opts.CellBits = 24
+
+	require.Error(t, Generate(&bytes.Buffer{}, nil, opts))
+}
@@ -0,0 +1,50 @@
+; Code generated by bf build --target=llvm. DO NOT EDIT.
+
+@tape = internal global [100 x i16] zeroinitializer
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+  %t1 = load i64, ptr %p
+  %t2 = getelementptr inbounds [100 x i16], ptr @tape, i64 0, i64 %t1
+  %t3 = load i16, ptr %t2
+  %t4 = add i16 %t3, 3
+  store i16 %t4, ptr %t2
+  br label %loop1
+loop1:
+  %t5 = load i64, ptr %p
+  %t6 = getelementptr inbounds [100 x i16], ptr @tape, i64 0, i64 %t5
+  %t7 = load i16, ptr %t6
+  %t8 = icmp ne i16 %t7, 0
+  br i1 %t8, label %body1, label %end6
+body1:
+  %t9 = load i64, ptr %p
+  %t10 = add i64 %t9, 1
+  store i64 %t10, ptr %p
+  %t11 = load i64, ptr %p
+  %t12 = getelementptr inbounds [100 x i16], ptr @tape, i64 0, i64 %t11
+  %t13 = load i16, ptr %t12
+  %t14 = add i16 %t13, 2
+  store i16 %t14, ptr %t12
+  %t15 = load i64, ptr %p
+  %t16 = add i64 %t15, -1
+  store i64 %t16, ptr %p
+  %t17 = load i64, ptr %p
+  %t18 = getelementptr inbounds [100 x i16], ptr @tape, i64 0, i64 %t17
+  %t19 = load i16, ptr %t18
+  %t20 = add i16 %t19, -1
+  store i16 %t20, ptr %t18
+  br label %loop1
+end6:
+  %t21 = load i64, ptr %p
+  %t22 = add i64 %t21, 1
+  store i64 %t22, ptr %p
+  %t23 = load i64, ptr %p
+  %t24 = getelementptr inbounds [100 x i16], ptr @tape, i64 0, i64 %t23
+  store i16 0, ptr %t24
+  %t25 = load i64, ptr %p
+  %t26 = add i64 %t25, -2
+  store i64 %t26, ptr %p
+  ret i32 0
+}
@@ -0,0 +1,55 @@
+; Code generated by bf build --target=llvm. DO NOT EDIT.
+
+@tape = internal global [16 x i32] zeroinitializer
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+  %t1 = call i32 @getchar()
+  %t2 = icmp eq i32 %t1, -1
+  br i1 %t2, label %eof0, label %read0
+eof0:
+  %t3 = load i64, ptr %p
+  %t4 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t3
+  store i32 0, ptr %t4
+  br label %next0
+read0:
+  %t5 = load i64, ptr %p
+  %t6 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t5
+  store i32 %t1, ptr %t6
+  br label %next0
+next0:
+  br label %loop1
+loop1:
+  %t7 = load i64, ptr %p
+  %t8 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t7
+  %t9 = load i32, ptr %t8
+  %t10 = icmp ne i32 %t9, 0
+  br i1 %t10, label %body1, label %end4
+body1:
+  %t11 = load i64, ptr %p
+  %t12 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t11
+  %t13 = load i32, ptr %t12
+  %t14 = call i32 @putchar(i32 %t13)
+  %t15 = call i32 @getchar()
+  %t16 = icmp eq i32 %t15, -1
+  br i1 %t16, label %eof3, label %read3
+eof3:
+  %t17 = load i64, ptr %p
+  %t18 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t17
+  store i32 0, ptr %t18
+  br label %next3
+read3:
+  %t19 = load i64, ptr %p
+  %t20 = getelementptr inbounds [16 x i32], ptr @tape, i64 0, i64 %t19
+  store i32 %t15, ptr %t20
+  br label %next3
+next3:
+  br label %loop1
+end4:
+  ret i32 0
+}
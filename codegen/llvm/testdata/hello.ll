@@ -0,0 +1,403 @@
+; Code generated by bf build --target=llvm. DO NOT EDIT.
+
+@tape = internal global [4096 x i8] zeroinitializer
+@fmt = private unnamed_addr constant [36 x i8] c"failed to process [#cmd: %lld]: %s\0A\00"
+@msg.shift_right = private unnamed_addr constant [29 x i8] c"shift+ moves out of boundary\00"
+@msg.shift_left = private unnamed_addr constant [29 x i8] c"shift- moves out of boundary\00"
+@msg.eof = private unnamed_addr constant [26 x i8] c"failed to read value: EOF\00"
+
+declare i32 @putchar(i32)
+declare i32 @fflush(ptr)
+declare i32 @dprintf(i32, ptr, ...)
+declare void @exit(i32) noreturn
+
+define internal void @fail(i64 %cmd, ptr %msg) noreturn {
+entry:
+  %t1 = call i32 @fflush(ptr null)
+  %t2 = call i32 (i32, ptr, ...) @dprintf(i32 2, ptr @fmt, i64 %cmd, ptr %msg)
+  call void @exit(i32 1)
+  unreachable
+}
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+  %t1 = load i64, ptr %p
+  %t2 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t1
+  %t3 = load i8, ptr %t2
+  %t4 = add i8 %t3, 8
+  store i8 %t4, ptr %t2
+  br label %loop1
+loop1:
+  %t5 = load i64, ptr %p
+  %t6 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t5
+  %t7 = load i8, ptr %t6
+  %t8 = icmp ne i8 %t7, 0
+  br i1 %t8, label %body1, label %end29
+body1:
+  %t9 = load i64, ptr %p
+  %t10 = add i64 %t9, 1
+  %t11 = icmp uge i64 %t10, 4096
+  br i1 %t11, label %fail2, label %move2
+fail2:
+  call void @fail(i64 9, ptr @msg.shift_right)
+  unreachable
+move2:
+  store i64 %t10, ptr %p
+  %t12 = load i64, ptr %p
+  %t13 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t12
+  %t14 = load i8, ptr %t13
+  %t15 = add i8 %t14, 4
+  store i8 %t15, ptr %t13
+  br label %loop4
+loop4:
+  %t16 = load i64, ptr %p
+  %t17 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t16
+  %t18 = load i8, ptr %t17
+  %t19 = icmp ne i8 %t18, 0
+  br i1 %t19, label %body4, label %end15
+body4:
+  %t20 = load i64, ptr %p
+  %t21 = add i64 %t20, 1
+  %t22 = icmp uge i64 %t21, 4096
+  br i1 %t22, label %fail5, label %move5
+fail5:
+  call void @fail(i64 15, ptr @msg.shift_right)
+  unreachable
+move5:
+  store i64 %t21, ptr %p
+  %t23 = load i64, ptr %p
+  %t24 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t23
+  %t25 = load i8, ptr %t24
+  %t26 = add i8 %t25, 2
+  store i8 %t26, ptr %t24
+  %t27 = load i64, ptr %p
+  %t28 = add i64 %t27, 1
+  %t29 = icmp uge i64 %t28, 4096
+  br i1 %t29, label %fail7, label %move7
+fail7:
+  call void @fail(i64 18, ptr @msg.shift_right)
+  unreachable
+move7:
+  store i64 %t28, ptr %p
+  %t30 = load i64, ptr %p
+  %t31 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t30
+  %t32 = load i8, ptr %t31
+  %t33 = add i8 %t32, 3
+  store i8 %t33, ptr %t31
+  %t34 = load i64, ptr %p
+  %t35 = add i64 %t34, 1
+  %t36 = icmp uge i64 %t35, 4096
+  br i1 %t36, label %fail9, label %move9
+fail9:
+  call void @fail(i64 22, ptr @msg.shift_right)
+  unreachable
+move9:
+  store i64 %t35, ptr %p
+  %t37 = load i64, ptr %p
+  %t38 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t37
+  %t39 = load i8, ptr %t38
+  %t40 = add i8 %t39, 3
+  store i8 %t40, ptr %t38
+  %t41 = load i64, ptr %p
+  %t42 = add i64 %t41, 1
+  %t43 = icmp uge i64 %t42, 4096
+  br i1 %t43, label %fail11, label %move11
+fail11:
+  call void @fail(i64 26, ptr @msg.shift_right)
+  unreachable
+move11:
+  store i64 %t42, ptr %p
+  %t44 = load i64, ptr %p
+  %t45 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t44
+  %t46 = load i8, ptr %t45
+  %t47 = add i8 %t46, 1
+  store i8 %t47, ptr %t45
+  %t48 = load i64, ptr %p
+  %t49 = add i64 %t48, -4
+  %t50 = icmp ult i64 %t48, 4
+  br i1 %t50, label %fail13, label %move13
+fail13:
+  %t51 = add i64 %t48, 28
+  call void @fail(i64 %t51, ptr @msg.shift_left)
+  unreachable
+move13:
+  store i64 %t49, ptr %p
+  %t52 = load i64, ptr %p
+  %t53 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t52
+  %t54 = load i8, ptr %t53
+  %t55 = add i8 %t54, -1
+  store i8 %t55, ptr %t53
+  br label %loop4
+end15:
+  %t56 = load i64, ptr %p
+  %t57 = add i64 %t56, 1
+  %t58 = icmp uge i64 %t57, 4096
+  br i1 %t58, label %fail16, label %move16
+fail16:
+  call void @fail(i64 34, ptr @msg.shift_right)
+  unreachable
+move16:
+  store i64 %t57, ptr %p
+  %t59 = load i64, ptr %p
+  %t60 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t59
+  %t61 = load i8, ptr %t60
+  %t62 = add i8 %t61, 1
+  store i8 %t62, ptr %t60
+  %t63 = load i64, ptr %p
+  %t64 = add i64 %t63, 1
+  %t65 = icmp uge i64 %t64, 4096
+  br i1 %t65, label %fail18, label %move18
+fail18:
+  call void @fail(i64 36, ptr @msg.shift_right)
+  unreachable
+move18:
+  store i64 %t64, ptr %p
+  %t66 = load i64, ptr %p
+  %t67 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t66
+  %t68 = load i8, ptr %t67
+  %t69 = add i8 %t68, 1
+  store i8 %t69, ptr %t67
+  %t70 = load i64, ptr %p
+  %t71 = add i64 %t70, 1
+  %t72 = icmp uge i64 %t71, 4096
+  br i1 %t72, label %fail20, label %move20
+fail20:
+  call void @fail(i64 38, ptr @msg.shift_right)
+  unreachable
+move20:
+  store i64 %t71, ptr %p
+  %t73 = load i64, ptr %p
+  %t74 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t73
+  %t75 = load i8, ptr %t74
+  %t76 = add i8 %t75, -1
+  store i8 %t76, ptr %t74
+  %t77 = load i64, ptr %p
+  %t78 = add i64 %t77, 2
+  %t79 = icmp uge i64 %t78, 4096
+  br i1 %t79, label %fail22, label %move22
+fail22:
+  %t80 = sub i64 4135, %t77
+  call void @fail(i64 %t80, ptr @msg.shift_right)
+  unreachable
+move22:
+  store i64 %t78, ptr %p
+  %t81 = load i64, ptr %p
+  %t82 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t81
+  %t83 = load i8, ptr %t82
+  %t84 = add i8 %t83, 1
+  store i8 %t84, ptr %t82
+  br label %loop24
+loop24:
+  %t85 = load i64, ptr %p
+  %t86 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t85
+  %t87 = load i8, ptr %t86
+  %t88 = icmp ne i8 %t87, 0
+  br i1 %t88, label %body24, label %end26
+body24:
+  %t89 = load i64, ptr %p
+  %t90 = add i64 %t89, -1
+  %t91 = icmp ult i64 %t89, 1
+  br i1 %t91, label %fail25, label %move25
+fail25:
+  call void @fail(i64 44, ptr @msg.shift_left)
+  unreachable
+move25:
+  store i64 %t90, ptr %p
+  br label %loop24
+end26:
+  %t92 = load i64, ptr %p
+  %t93 = add i64 %t92, -1
+  %t94 = icmp ult i64 %t92, 1
+  br i1 %t94, label %fail27, label %move27
+fail27:
+  call void @fail(i64 46, ptr @msg.shift_left)
+  unreachable
+move27:
+  store i64 %t93, ptr %p
+  %t95 = load i64, ptr %p
+  %t96 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t95
+  %t97 = load i8, ptr %t96
+  %t98 = add i8 %t97, -1
+  store i8 %t98, ptr %t96
+  br label %loop1
+end29:
+  %t99 = load i64, ptr %p
+  %t100 = add i64 %t99, 2
+  %t101 = icmp uge i64 %t100, 4096
+  br i1 %t101, label %fail30, label %move30
+fail30:
+  %t102 = sub i64 4144, %t99
+  call void @fail(i64 %t102, ptr @msg.shift_right)
+  unreachable
+move30:
+  store i64 %t100, ptr %p
+  %t103 = load i64, ptr %p
+  %t104 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t103
+  %t105 = load i8, ptr %t104
+  %t106 = zext i8 %t105 to i32
+  %t107 = call i32 @putchar(i32 %t106)
+  %t108 = load i64, ptr %p
+  %t109 = add i64 %t108, 1
+  %t110 = icmp uge i64 %t109, 4096
+  br i1 %t110, label %fail32, label %move32
+fail32:
+  call void @fail(i64 52, ptr @msg.shift_right)
+  unreachable
+move32:
+  store i64 %t109, ptr %p
+  %t111 = load i64, ptr %p
+  %t112 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t111
+  %t113 = load i8, ptr %t112
+  %t114 = add i8 %t113, -3
+  store i8 %t114, ptr %t112
+  %t115 = load i64, ptr %p
+  %t116 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t115
+  %t117 = load i8, ptr %t116
+  %t118 = zext i8 %t117 to i32
+  %t119 = call i32 @putchar(i32 %t118)
+  %t120 = load i64, ptr %p
+  %t121 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t120
+  %t122 = load i8, ptr %t121
+  %t123 = add i8 %t122, 7
+  store i8 %t123, ptr %t121
+  %t124 = load i64, ptr %p
+  %t125 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t124
+  %t126 = load i8, ptr %t125
+  %t127 = zext i8 %t126 to i32
+  %t128 = call i32 @putchar(i32 %t127)
+  %t129 = load i64, ptr %p
+  %t130 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t129
+  %t131 = load i8, ptr %t130
+  %t132 = zext i8 %t131 to i32
+  %t133 = call i32 @putchar(i32 %t132)
+  %t134 = load i64, ptr %p
+  %t135 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t134
+  %t136 = load i8, ptr %t135
+  %t137 = add i8 %t136, 3
+  store i8 %t137, ptr %t135
+  %t138 = load i64, ptr %p
+  %t139 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t138
+  %t140 = load i8, ptr %t139
+  %t141 = zext i8 %t140 to i32
+  %t142 = call i32 @putchar(i32 %t141)
+  %t143 = load i64, ptr %p
+  %t144 = add i64 %t143, 2
+  %t145 = icmp uge i64 %t144, 4096
+  br i1 %t145, label %fail40, label %move40
+fail40:
+  %t146 = sub i64 4165, %t143
+  call void @fail(i64 %t146, ptr @msg.shift_right)
+  unreachable
+move40:
+  store i64 %t144, ptr %p
+  %t147 = load i64, ptr %p
+  %t148 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t147
+  %t149 = load i8, ptr %t148
+  %t150 = zext i8 %t149 to i32
+  %t151 = call i32 @putchar(i32 %t150)
+  %t152 = load i64, ptr %p
+  %t153 = add i64 %t152, -1
+  %t154 = icmp ult i64 %t152, 1
+  br i1 %t154, label %fail42, label %move42
+fail42:
+  call void @fail(i64 73, ptr @msg.shift_left)
+  unreachable
+move42:
+  store i64 %t153, ptr %p
+  %t155 = load i64, ptr %p
+  %t156 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t155
+  %t157 = load i8, ptr %t156
+  %t158 = add i8 %t157, -1
+  store i8 %t158, ptr %t156
+  %t159 = load i64, ptr %p
+  %t160 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t159
+  %t161 = load i8, ptr %t160
+  %t162 = zext i8 %t161 to i32
+  %t163 = call i32 @putchar(i32 %t162)
+  %t164 = load i64, ptr %p
+  %t165 = add i64 %t164, -1
+  %t166 = icmp ult i64 %t164, 1
+  br i1 %t166, label %fail45, label %move45
+fail45:
+  call void @fail(i64 76, ptr @msg.shift_left)
+  unreachable
+move45:
+  store i64 %t165, ptr %p
+  %t167 = load i64, ptr %p
+  %t168 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t167
+  %t169 = load i8, ptr %t168
+  %t170 = zext i8 %t169 to i32
+  %t171 = call i32 @putchar(i32 %t170)
+  %t172 = load i64, ptr %p
+  %t173 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t172
+  %t174 = load i8, ptr %t173
+  %t175 = add i8 %t174, 3
+  store i8 %t175, ptr %t173
+  %t176 = load i64, ptr %p
+  %t177 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t176
+  %t178 = load i8, ptr %t177
+  %t179 = zext i8 %t178 to i32
+  %t180 = call i32 @putchar(i32 %t179)
+  %t181 = load i64, ptr %p
+  %t182 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t181
+  %t183 = load i8, ptr %t182
+  %t184 = add i8 %t183, -6
+  store i8 %t184, ptr %t182
+  %t185 = load i64, ptr %p
+  %t186 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t185
+  %t187 = load i8, ptr %t186
+  %t188 = zext i8 %t187 to i32
+  %t189 = call i32 @putchar(i32 %t188)
+  %t190 = load i64, ptr %p
+  %t191 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t190
+  %t192 = load i8, ptr %t191
+  %t193 = add i8 %t192, -8
+  store i8 %t193, ptr %t191
+  %t194 = load i64, ptr %p
+  %t195 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t194
+  %t196 = load i8, ptr %t195
+  %t197 = zext i8 %t196 to i32
+  %t198 = call i32 @putchar(i32 %t197)
+  %t199 = load i64, ptr %p
+  %t200 = add i64 %t199, 2
+  %t201 = icmp uge i64 %t200, 4096
+  br i1 %t201, label %fail53, label %move53
+fail53:
+  %t202 = sub i64 4193, %t199
+  call void @fail(i64 %t202, ptr @msg.shift_right)
+  unreachable
+move53:
+  store i64 %t200, ptr %p
+  %t203 = load i64, ptr %p
+  %t204 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t203
+  %t205 = load i8, ptr %t204
+  %t206 = add i8 %t205, 1
+  store i8 %t206, ptr %t204
+  %t207 = load i64, ptr %p
+  %t208 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t207
+  %t209 = load i8, ptr %t208
+  %t210 = zext i8 %t209 to i32
+  %t211 = call i32 @putchar(i32 %t210)
+  %t212 = load i64, ptr %p
+  %t213 = add i64 %t212, 1
+  %t214 = icmp uge i64 %t213, 4096
+  br i1 %t214, label %fail56, label %move56
+fail56:
+  call void @fail(i64 102, ptr @msg.shift_right)
+  unreachable
+move56:
+  store i64 %t213, ptr %p
+  %t215 = load i64, ptr %p
+  %t216 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t215
+  %t217 = load i8, ptr %t216
+  %t218 = add i8 %t217, 2
+  store i8 %t218, ptr %t216
+  %t219 = load i64, ptr %p
+  %t220 = getelementptr inbounds [4096 x i8], ptr @tape, i64 0, i64 %t219
+  %t221 = load i8, ptr %t220
+  %t222 = zext i8 %t221 to i32
+  %t223 = call i32 @putchar(i32 %t222)
+  ret i32 0
+}
@@ -0,0 +1,53 @@
+; Code generated by bf build --target=llvm. DO NOT EDIT.
+
+@tape = internal global [4096 x i64] zeroinitializer
+
+declare i32 @getchar()
+declare i32 @putchar(i32)
+
+define i32 @main() {
+entry:
+  %p = alloca i64
+  store i64 0, ptr %p
+  %t1 = call i32 @getchar()
+  %t2 = icmp eq i32 %t1, -1
+  br i1 %t2, label %eof0, label %read0
+eof0:
+  %t3 = load i64, ptr %p
+  %t4 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t3
+  store i64 -1, ptr %t4
+  br label %next0
+read0:
+  %t5 = zext i32 %t1 to i64
+  %t6 = load i64, ptr %p
+  %t7 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t6
+  store i64 %t5, ptr %t7
+  br label %next0
+next0:
+  %t8 = load i64, ptr %p
+  %t9 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t8
+  %t10 = load i64, ptr %t9
+  %t11 = trunc i64 %t10 to i32
+  %t12 = call i32 @putchar(i32 %t11)
+  %t13 = call i32 @getchar()
+  %t14 = icmp eq i32 %t13, -1
+  br i1 %t14, label %eof2, label %read2
+eof2:
+  %t15 = load i64, ptr %p
+  %t16 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t15
+  store i64 -1, ptr %t16
+  br label %next2
+read2:
+  %t17 = zext i32 %t13 to i64
+  %t18 = load i64, ptr %p
+  %t19 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t18
+  store i64 %t17, ptr %t19
+  br label %next2
+next2:
+  %t20 = load i64, ptr %p
+  %t21 = getelementptr inbounds [4096 x i64], ptr @tape, i64 0, i64 %t20
+  %t22 = load i64, ptr %t21
+  %t23 = trunc i64 %t22 to i32
+  %t24 = call i32 @putchar(i32 %t23)
+  ret i32 0
+}
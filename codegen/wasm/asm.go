@@ -0,0 +1,342 @@
+package wasm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// immKind is a kind of an instruction immediate
+type immKind byte
+
+const (
+	immNone immKind = iota
+	immI32
+	immI64
+	immLocal
+	immFunc
+	immDepth
+	immBlock
+	immMem
+)
+
+// opcode is a WebAssembly instruction
+type opcode struct {
+	name string
+	code byte
+	imm  immKind
+
+	// align is log2 of the natural alignment of memory instructions
+	align int
+}
+
+var (
+	opBlock  = opcode{name: "block", code: 0x02, imm: immBlock}
+	opLoop   = opcode{name: "loop", code: 0x03, imm: immBlock}
+	opIf     = opcode{name: "if", code: 0x04, imm: immBlock}
+	opElse   = opcode{name: "else", code: 0x05}
+	opEnd    = opcode{name: "end", code: 0x0B}
+	opBrIf   = opcode{name: "br_if", code: 0x0D, imm: immDepth}
+	opReturn = opcode{name: "return", code: 0x0F}
+	opCall   = opcode{name: "call", code: 0x10, imm: immFunc}
+
+	opLocalGet = opcode{name: "local.get", code: 0x20, imm: immLocal}
+	opLocalSet = opcode{name: "local.set", code: 0x21, imm: immLocal}
+
+	opI32Load    = opcode{name: "i32.load", code: 0x28, imm: immMem, align: 2}
+	opI64Load    = opcode{name: "i64.load", code: 0x29, imm: immMem, align: 3}
+	opI32Load8S  = opcode{name: "i32.load8_s", code: 0x2C, imm: immMem, align: 0}
+	opI32Load8U  = opcode{name: "i32.load8_u", code: 0x2D, imm: immMem, align: 0}
+	opI32Load16S = opcode{name: "i32.load16_s", code: 0x2E, imm: immMem, align: 1}
+	opI32Load16U = opcode{name: "i32.load16_u", code: 0x2F, imm: immMem, align: 1}
+	opI32Store   = opcode{name: "i32.store", code: 0x36, imm: immMem, align: 2}
+	opI64Store   = opcode{name: "i64.store", code: 0x37, imm: immMem, align: 3}
+	opI32Store8  = opcode{name: "i32.store8", code: 0x3A, imm: immMem, align: 0}
+	opI32Store16 = opcode{name: "i32.store16", code: 0x3B, imm: immMem, align: 1}
+
+	opI32Const = opcode{name: "i32.const", code: 0x41, imm: immI32}
+	opI64Const = opcode{name: "i64.const", code: 0x42, imm: immI64}
+
+	opI32Eqz = opcode{name: "i32.eqz", code: 0x45}
+	opI32Ne  = opcode{name: "i32.ne", code: 0x47}
+	opI32LtU = opcode{name: "i32.lt_u", code: 0x49}
+	opI32GeU = opcode{name: "i32.ge_u", code: 0x4F}
+	opI64Eqz = opcode{name: "i64.eqz", code: 0x50}
+	opI64Ne  = opcode{name: "i64.ne", code: 0x52}
+
+	opI32Add  = opcode{name: "i32.add", code: 0x6A}
+	opI32Sub  = opcode{name: "i32.sub", code: 0x6B}
+	opI32ShrU = opcode{name: "i32.shr_u", code: 0x76}
+	opI64Add  = opcode{name: "i64.add", code: 0x7C}
+
+	opI32WrapI64    = opcode{name: "i32.wrap_i64", code: 0xA7}
+	opI64ExtendI32S = opcode{name: "i64.extend_i32_s", code: 0xAC}
+	opI64ExtendI32U = opcode{name: "i64.extend_i32_u", code: 0xAD}
+)
+
+// insn is an instruction with its immediate
+type insn struct {
+	op  opcode
+	arg int64
+}
+
+// valType is a WebAssembly value type
+type valType byte
+
+const (
+	i32 valType = 0x7F
+	i64 valType = 0x7E
+)
+
+func (t valType) String() string {
+	if t == i64 {
+		return "i64"
+	}
+
+	return "i32"
+}
+
+// funcType is a function signature
+type funcType struct {
+	params  []valType
+	results []valType
+}
+
+// function is an imported or a defined function
+type function struct {
+	name string
+	typ  funcType
+
+	// module and field are set for imported functions
+	module, field string
+
+	// export is the export name of a defined function
+	export string
+}
+
+// module is a WebAssembly module with imported functions and a single defined function
+type module struct {
+	imports []function
+	fn      function
+
+	// memPages is the initial memory size in 64KiB pages, the memory is exported as "memory"
+	memPages int
+
+	locals     []valType
+	localNames []string
+
+	body []insn
+}
+
+// encode writes the binary format of the module
+func (m *module) encode(w io.Writer) error {
+	var out bytes.Buffer
+
+	out.Write([]byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00})
+
+	funcs := append(append([]function{}, m.imports...), m.fn)
+
+	// type section: a type for every function
+	var sec bytes.Buffer
+	writeU(&sec, uint64(len(funcs)))
+	for _, f := range funcs {
+		sec.WriteByte(0x60)
+		writeTypes(&sec, f.typ.params)
+		writeTypes(&sec, f.typ.results)
+	}
+	writeSection(&out, 1, sec.Bytes())
+
+	// import section
+	sec.Reset()
+	writeU(&sec, uint64(len(m.imports)))
+	for i, f := range m.imports {
+		writeName(&sec, f.module)
+		writeName(&sec, f.field)
+		sec.WriteByte(0x00)
+		writeU(&sec, uint64(i))
+	}
+	writeSection(&out, 2, sec.Bytes())
+
+	// function section
+	sec.Reset()
+	writeU(&sec, 1)
+	writeU(&sec, uint64(len(m.imports)))
+	writeSection(&out, 3, sec.Bytes())
+
+	// memory section
+	sec.Reset()
+	writeU(&sec, 1)
+	sec.WriteByte(0x00)
+	writeU(&sec, uint64(m.memPages))
+	writeSection(&out, 5, sec.Bytes())
+
+	// export section
+	sec.Reset()
+	writeU(&sec, 2)
+	writeName(&sec, "memory")
+	sec.WriteByte(0x02)
+	writeU(&sec, 0)
+	writeName(&sec, m.fn.export)
+	sec.WriteByte(0x00)
+	writeU(&sec, uint64(len(m.imports)))
+	writeSection(&out, 7, sec.Bytes())
+
+	// code section
+	var code bytes.Buffer
+	writeU(&code, uint64(len(m.locals)))
+	for _, t := range m.locals {
+		writeU(&code, 1)
+		code.WriteByte(byte(t))
+	}
+
+	for _, in := range m.body {
+		code.WriteByte(in.op.code)
+
+		switch in.op.imm {
+		case immI32, immI64:
+			writeS(&code, in.arg)
+		case immLocal, immFunc, immDepth:
+			writeU(&code, uint64(in.arg))
+		case immBlock:
+			code.WriteByte(0x40)
+		case immMem:
+			writeU(&code, uint64(in.op.align))
+			writeU(&code, 0)
+		}
+	}
+	code.WriteByte(opEnd.code)
+
+	sec.Reset()
+	writeU(&sec, 1)
+	writeU(&sec, uint64(code.Len()))
+	sec.Write(code.Bytes())
+	writeSection(&out, 10, sec.Bytes())
+
+	_, err := w.Write(out.Bytes())
+
+	return err
+}
+
+// text writes the text format (WAT) of the module
+func (m *module) text(w io.Writer) error {
+	var out bytes.Buffer
+
+	out.WriteString("(module\n")
+
+	for _, f := range m.imports {
+		fmt.Fprintf(&out, "  (import %q %q (func $%s%s))\n", f.module, f.field, f.name, signature(f.typ))
+	}
+
+	fmt.Fprintf(&out, "  (memory (export \"memory\") %d)\n", m.memPages)
+	fmt.Fprintf(&out, "  (func $%s (export %q)%s\n", m.fn.name, m.fn.export, signature(m.fn.typ))
+
+	out.WriteString("   ")
+	for i, t := range m.locals {
+		fmt.Fprintf(&out, " (local $%s %s)", m.localNames[i], t)
+	}
+	out.WriteString("\n")
+
+	funcs := append(append([]function{}, m.imports...), m.fn)
+
+	depth := 2
+	for _, in := range m.body {
+		if in.op == opEnd || in.op == opElse {
+			depth--
+		}
+
+		out.WriteString(strings.Repeat("  ", depth))
+		out.WriteString(in.op.name)
+
+		switch in.op.imm {
+		case immI32, immI64, immDepth:
+			fmt.Fprintf(&out, " %d", in.arg)
+		case immLocal:
+			fmt.Fprintf(&out, " $%s", m.localNames[in.arg])
+		case immFunc:
+			fmt.Fprintf(&out, " $%s", funcs[in.arg].name)
+		}
+
+		out.WriteString("\n")
+
+		if in.op.imm == immBlock || in.op == opElse {
+			depth++
+		}
+	}
+
+	out.WriteString("  )\n)\n")
+
+	_, err := w.Write(out.Bytes())
+
+	return err
+}
+
+func signature(t funcType) string {
+	var sb strings.Builder
+
+	if len(t.params) > 0 {
+		sb.WriteString(" (param")
+		for _, p := range t.params {
+			sb.WriteString(" " + p.String())
+		}
+		sb.WriteString(")")
+	}
+
+	if len(t.results) > 0 {
+		sb.WriteString(" (result")
+		for _, r := range t.results {
+			sb.WriteString(" " + r.String())
+		}
+		sb.WriteString(")")
+	}
+
+	return sb.String()
+}
+
+func writeSection(w *bytes.Buffer, id byte, content []byte) {
+	w.WriteByte(id)
+	writeU(w, uint64(len(content)))
+	w.Write(content)
+}
+
+func writeTypes(w *bytes.Buffer, types []valType) {
+	writeU(w, uint64(len(types)))
+	for _, t := range types {
+		w.WriteByte(byte(t))
+	}
+}
+
+func writeName(w *bytes.Buffer, name string) {
+	writeU(w, uint64(len(name)))
+	w.WriteString(name)
+}
+
+// writeU writes unsigned LEB128
+func writeU(w *bytes.Buffer, v uint64) {
+	for {
+		b := byte(v & 0x7F)
+		v >>= 7
+
+		if v == 0 {
+			w.WriteByte(b)
+			return
+		}
+
+		w.WriteByte(b | 0x80)
+	}
+}
+
+// writeS writes signed LEB128
+func writeS(w *bytes.Buffer, v int64) {
+	for {
+		b := byte(v & 0x7F)
+		v >>= 7
+
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			w.WriteByte(b)
+			return
+		}
+
+		w.WriteByte(b | 0x80)
+	}
+}
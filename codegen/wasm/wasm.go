@@ -0,0 +1,483 @@
+// Package wasm generates WebAssembly modules from brainfuck programs.
+//
+// A module imports input and output functions that mirror InputReader and OutputWriter:
+//
+//	(import "bf" "input" (func $input (param i32 i32) (result i64 i32)))  ;; cmd, cell index -> value, Status
+//	(import "bf" "output" (func $output (param i32 i64) (result i32)))    ;; cmd, value -> Status
+//
+// and exports the tape as "memory" (cells are little-endian starting at address 0) and the program as
+//
+//	(func $run (export "run") (result i32 i32))  ;; -> ExitCode, cmd
+//
+// where cmd is the offset of the failed command, i.e. CmdPtr of the interpreter.
+package wasm
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+)
+
+// Status is a result of imported input and output functions
+type Status int32
+
+const (
+	// StatusOK means that the value was read or written
+	StatusOK Status = iota
+
+	// StatusEOF means the end of input
+	StatusEOF
+
+	// StatusError means that the host failed to read or write the value
+	StatusError
+)
+
+// ExitCode is the first result of run function
+type ExitCode int32
+
+const (
+	// ExitOK means that the program is over
+	ExitOK ExitCode = iota
+
+	// ExitShiftRight means that the data pointer moved out of the tape to the right
+	ExitShiftRight
+
+	// ExitShiftLeft means that the data pointer moved out of the tape to the left
+	ExitShiftLeft
+
+	// ExitEOF means the end of input with EOFError policy
+	ExitEOF
+
+	// ExitInputError means that input returned StatusError
+	ExitInputError
+
+	// ExitOutputError means that output returned StatusError
+	ExitOutputError
+)
+
+// Err returns the error that BfInterpreter reports in the same case.
+// Host errors of input and output are wrapped like BfInterpreter wraps reader and writer errors.
+func (c ExitCode) Err(hostErr error) error {
+	switch c {
+	case ExitOK:
+		return nil
+	case ExitShiftRight:
+		return fmt.Errorf("shift+ moves out of boundary")
+	case ExitShiftLeft:
+		return fmt.Errorf("shift- moves out of boundary")
+	case ExitEOF:
+		return fmt.Errorf("failed to read value: %w", io.EOF)
+	case ExitInputError:
+		return fmt.Errorf("failed to read value: %w", hostErr)
+	case ExitOutputError:
+		return fmt.Errorf("failed to write value: %w", hostErr)
+	default:
+		return fmt.Errorf("unknown exit code: %d", c)
+	}
+}
+
+// Cmd returns the command that fails with the exit code
+func (c ExitCode) Cmd() brainfuck.CmdType {
+	switch c {
+	case ExitShiftRight:
+		return '>'
+	case ExitShiftLeft:
+		return '<'
+	case ExitEOF, ExitInputError:
+		return ','
+	case ExitOutputError:
+		return '.'
+	default:
+		return 0
+	}
+}
+
+// maxTapeBytes keeps addresses in i32 range. The tape size is compared with i32.const that is signed.
+const maxTapeBytes = 1<<31 - 1
+
+const pageSize = 1 << 16
+
+// Function indexes
+const (
+	funcInput = iota
+	funcOutput
+)
+
+// Local variables indexes
+const (
+	// localP is the address of the current cell
+	localP = iota
+
+	// localS is the status returned by input
+	localS
+
+	// localV is the value returned by input
+	localV
+)
+
+// Generate writes the binary WebAssembly module of the program
+func Generate(w io.Writer, prog ir.Program, opts codegen.Options) error {
+	m, err := build(prog, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := m.encode(w); err != nil {
+		return fmt.Errorf("failed to write module: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateText writes the text format (WAT) of the module
+func GenerateText(w io.Writer, prog ir.Program, opts codegen.Options) error {
+	m, err := build(prog, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := m.text(w); err != nil {
+		return fmt.Errorf("failed to write module: %w", err)
+	}
+
+	return nil
+}
+
+func build(prog ir.Program, opts codegen.Options) (*module, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := generator{
+		opts:      opts,
+		cellBytes: opts.CellBits / 8,
+	}
+
+	tapeBytes := int64(opts.TapeSize) * int64(g.cellBytes)
+	if tapeBytes > maxTapeBytes {
+		return nil, fmt.Errorf("tape is too large: %d bytes", tapeBytes)
+	}
+
+	for _, in := range prog {
+		if err := g.instr(in); err != nil {
+			return nil, err
+		}
+	}
+
+	g.emit(opI32Const, int64(ExitOK))
+	g.emit(opI32Const, 0)
+
+	return &module{
+		imports: []function{
+			{
+				name:   "input",
+				module: "bf",
+				field:  "input",
+				typ:    funcType{params: []valType{i32, i32}, results: []valType{i64, i32}},
+			},
+			{
+				name:   "output",
+				module: "bf",
+				field:  "output",
+				typ:    funcType{params: []valType{i32, i64}, results: []valType{i32}},
+			},
+		},
+		fn: function{
+			name:   "run",
+			export: "run",
+			typ:    funcType{results: []valType{i32, i32}},
+		},
+		memPages:   int((tapeBytes + pageSize - 1) / pageSize),
+		locals:     []valType{i32, i32, i64},
+		localNames: []string{"p", "s", "v"},
+		body:       g.body,
+	}, nil
+}
+
+type generator struct {
+	opts      codegen.Options
+	cellBytes int
+	body      []insn
+}
+
+func (g *generator) emit(op opcode, arg ...int64) {
+	in := insn{op: op}
+	if len(arg) > 0 {
+		in.arg = arg[0]
+	}
+
+	g.body = append(g.body, in)
+}
+
+func (g *generator) wide() bool {
+	return g.opts.CellBits == 64
+}
+
+// load pushes the current cell, cells narrower than 64 bits are pushed as i32
+func (g *generator) load() {
+	g.emit(opLocalGet, localP)
+
+	switch g.opts.CellBits {
+	case 8:
+		if g.opts.Signed {
+			g.emit(opI32Load8S)
+		} else {
+			g.emit(opI32Load8U)
+		}
+	case 16:
+		if g.opts.Signed {
+			g.emit(opI32Load16S)
+		} else {
+			g.emit(opI32Load16U)
+		}
+	case 32:
+		g.emit(opI32Load)
+	default:
+		g.emit(opI64Load)
+	}
+}
+
+// store pops a value and stores it to the current cell, the address should be pushed before the value
+func (g *generator) store() {
+	switch g.opts.CellBits {
+	case 8:
+		g.emit(opI32Store8)
+	case 16:
+		g.emit(opI32Store16)
+	case 32:
+		g.emit(opI32Store)
+	default:
+		g.emit(opI64Store)
+	}
+}
+
+// constant pushes a cell constant
+func (g *generator) constant(v uint64) {
+	if g.wide() {
+		g.emit(opI64Const, int64(v))
+		return
+	}
+
+	g.emit(opI32Const, int64(int32(uint32(v))))
+}
+
+// exit returns from run with the exit code and the command offset
+func (g *generator) exit(code ExitCode, offset int) {
+	g.emit(opI32Const, int64(code))
+	g.emit(opI32Const, int64(offset))
+	g.emit(opReturn)
+}
+
+func (g *generator) instr(in ir.Instr) error {
+	switch in.Op {
+	case ir.OpAdd:
+		g.emit(opLocalGet, localP)
+		g.load()
+		g.constant(g.opts.Unsigned(in.Arg))
+		if g.wide() {
+			g.emit(opI64Add)
+		} else {
+			g.emit(opI32Add)
+		}
+		g.store()
+
+	case ir.OpMove:
+		return g.move(in)
+
+	case ir.OpOut:
+		g.out(in)
+
+	case ir.OpIn:
+		g.in(in)
+
+	case ir.OpLoop:
+		// block $exit (if cell == 0 br $exit) loop $body
+		g.emit(opBlock)
+		g.load()
+		if g.wide() {
+			g.emit(opI64Eqz)
+		} else {
+			g.emit(opI32Eqz)
+		}
+		g.emit(opBrIf, 0)
+		g.emit(opLoop)
+
+	case ir.OpEnd:
+		// (if cell != 0 br $body) end end
+		g.load()
+		if g.wide() {
+			g.emit(opI64Const, 0)
+			g.emit(opI64Ne)
+		}
+		g.emit(opBrIf, 0)
+		g.emit(opEnd)
+		g.emit(opEnd)
+
+	case ir.OpClear:
+		g.emit(opLocalGet, localP)
+		g.constant(0)
+		g.store()
+
+	default:
+		return fmt.Errorf("unknown instruction: %s", in.Op)
+	}
+
+	return nil
+}
+
+// cellIndex pushes the index of the current cell
+func (g *generator) cellIndex() {
+	g.emit(opLocalGet, localP)
+	if shift := log2(g.cellBytes); shift > 0 {
+		g.emit(opI32Const, int64(shift))
+		g.emit(opI32ShrU)
+	}
+}
+
+// move changes the address of the current cell. A folded move fails on the command that reaches the boundary,
+// so its offset is computed from the current cell (see ir.Instr).
+func (g *generator) move(in ir.Instr) error {
+	delta := int64(in.Arg) * int64(g.cellBytes)
+	if delta > maxTapeBytes || -delta > maxTapeBytes {
+		return fmt.Errorf("move is too large [#cmd: %d]: %d cells", in.Offset, in.Arg)
+	}
+
+	if delta > 0 {
+		if g.opts.BoundsCheck {
+			g.emit(opLocalGet, localP)
+			g.emit(opI32Const, delta)
+			g.emit(opI32Add)
+			g.emit(opI32Const, int64(g.opts.TapeSize*g.cellBytes))
+			g.emit(opI32GeU)
+			g.emit(opIf)
+
+			switch {
+			case in.Arg == 1:
+				g.exit(ExitShiftRight, in.Offset)
+
+			// the offset of the failed command is returned as i32
+			case int64(in.Offset)+int64(g.opts.TapeSize)-1 > math.MaxInt32:
+				return fmt.Errorf("program is too large for the tape [#cmd: %d]", in.Offset)
+
+			default:
+				g.emit(opI32Const, int64(ExitShiftRight))
+				g.emit(opI32Const, int64(in.Offset+g.opts.TapeSize-1))
+				g.cellIndex()
+				g.emit(opI32Sub)
+				g.emit(opReturn)
+			}
+
+			g.emit(opEnd)
+		}
+
+		g.emit(opLocalGet, localP)
+		g.emit(opI32Const, delta)
+		g.emit(opI32Add)
+		g.emit(opLocalSet, localP)
+
+		return nil
+	}
+
+	if g.opts.BoundsCheck {
+		g.emit(opLocalGet, localP)
+		g.emit(opI32Const, -delta)
+		g.emit(opI32LtU)
+		g.emit(opIf)
+
+		if in.Arg == -1 {
+			g.exit(ExitShiftLeft, in.Offset)
+		} else {
+			g.emit(opI32Const, int64(ExitShiftLeft))
+			g.cellIndex()
+			g.emit(opI32Const, int64(in.Offset))
+			g.emit(opI32Add)
+			g.emit(opReturn)
+		}
+
+		g.emit(opEnd)
+	}
+
+	g.emit(opLocalGet, localP)
+	g.emit(opI32Const, -delta)
+	g.emit(opI32Sub)
+	g.emit(opLocalSet, localP)
+
+	return nil
+}
+
+func (g *generator) out(in ir.Instr) {
+	g.emit(opI32Const, int64(in.Offset))
+	g.load()
+
+	if !g.wide() {
+		if g.opts.Signed {
+			g.emit(opI64ExtendI32S)
+		} else {
+			g.emit(opI64ExtendI32U)
+		}
+	}
+
+	g.emit(opCall, funcOutput)
+	g.emit(opIf)
+	g.exit(ExitOutputError, in.Offset)
+	g.emit(opEnd)
+}
+
+func (g *generator) in(in ir.Instr) {
+	g.emit(opI32Const, int64(in.Offset))
+
+	g.cellIndex()
+
+	g.emit(opCall, funcInput)
+	g.emit(opLocalSet, localS)
+	g.emit(opLocalSet, localV)
+
+	g.emit(opLocalGet, localS)
+	g.emit(opIf)
+
+	// status is not OK: is it the end of input?
+	g.emit(opLocalGet, localS)
+	g.emit(opI32Const, int64(StatusEOF))
+	g.emit(opI32Ne)
+	g.emit(opIf)
+	g.exit(ExitInputError, in.Offset)
+	g.emit(opEnd)
+
+	switch g.opts.EOFPolicy {
+	case brainfuck.EOFError:
+		g.exit(ExitEOF, in.Offset)
+
+	case brainfuck.EOFZero:
+		g.emit(opLocalGet, localP)
+		g.constant(0)
+		g.store()
+
+	case brainfuck.EOFMinusOne:
+		g.emit(opLocalGet, localP)
+		g.constant(g.opts.Unsigned(-1))
+		g.store()
+	}
+
+	g.emit(opElse)
+	g.emit(opLocalGet, localP)
+	g.emit(opLocalGet, localV)
+	if !g.wide() {
+		g.emit(opI32WrapI64)
+	}
+	g.store()
+	g.emit(opEnd)
+}
+
+func log2(n int) int {
+	shift := 0
+	for n > 1 {
+		n >>= 1
+		shift++
+	}
+
+	return shift
+}
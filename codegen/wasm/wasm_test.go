@@ -0,0 +1,391 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/codegen"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"golang.org/x/exp/constraints"
+)
+
+const helloWorld = `++++++++[>++++[>++>+++>+++>+<<<<-]>+>+>->>+[<]<-]>>.>---.+++++++..+++.>>.<-.<.+++.------.--------.>>+.>++.`
+
+// failingWriter fails after limit values are written
+type failingWriter[DataType any] struct {
+	limit int
+}
+
+func (w *failingWriter[DataType]) Write(DataType) error {
+	if w.limit == 0 {
+		return errors.New("sink is full")
+	}
+
+	w.limit--
+
+	return nil
+}
+
+func (w *failingWriter[DataType]) Close() error {
+	return nil
+}
+
+// runModule runs a module with wazero. Input and output functions are backed by brainfuck reader and writer.
+func runModule[DataType constraints.Integer](
+	t *testing.T,
+	module []byte,
+	tapeSize int,
+	input brainfuck.InputReader[DataType],
+	output brainfuck.OutputWriter[DataType],
+) ([]DataType, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+
+	var hostErr error
+
+	_, err := r.NewHostModuleBuilder("bf").
+		NewFunctionBuilder().
+		WithGoFunction(api.GoFunc(func(ctx context.Context, stack []uint64) {
+			v, err := input.Read(prompt.Hint[DataType]{CmdPtr: int(uint32(stack[0])), DataPtr: int(uint32(stack[1]))})
+
+			switch {
+			case errors.Is(err, io.EOF):
+				stack[1] = uint64(StatusEOF)
+			case err != nil:
+				hostErr = err
+				stack[1] = uint64(StatusError)
+			default:
+				stack[0] = uint64(int64(v))
+				stack[1] = uint64(StatusOK)
+			}
+		}), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64, api.ValueTypeI32}).
+		Export("input").
+		NewFunctionBuilder().
+		WithGoFunction(api.GoFunc(func(ctx context.Context, stack []uint64) {
+			if err := output.Write(DataType(int64(stack[1]))); err != nil {
+				hostErr = err
+				stack[0] = uint64(StatusError)
+				return
+			}
+
+			stack[0] = uint64(StatusOK)
+		}), []api.ValueType{api.ValueTypeI32, api.ValueTypeI64}, []api.ValueType{api.ValueTypeI32}).
+		Export("output").
+		Instantiate(ctx)
+	require.NoError(t, err)
+
+	mod, err := r.Instantiate(ctx, module)
+	require.NoError(t, err)
+
+	res, err := mod.ExportedFunction("run").Call(ctx)
+	require.NoError(t, err)
+
+	if code := ExitCode(res[0]); code != ExitOK {
+		return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(res[1]), Cmd: code.Cmd(), Err: code.Err(hostErr)}
+	}
+
+	// reading the tape
+	var zero DataType
+	cellBytes := binary.Size(zero)
+
+	mem, ok := mod.Memory().Read(0, uint32(tapeSize*cellBytes))
+	require.True(t, ok)
+
+	data := make([]DataType, tapeSize)
+	require.NoError(t, binary.Read(bytes.NewReader(mem), binary.LittleEndian, data))
+
+	return data, nil
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		code   string
+		input  string
+		policy brainfuck.EOFPolicy
+	}
+
+	tests := map[string]Test{
+		"hello world": {
+			code: helloWorld,
+		},
+
+		"echo": {
+			code:   `,[.,]`,
+			input:  "echo me",
+			policy: brainfuck.EOFZero,
+		},
+
+		"cell wraps": {
+			code: `-.+.[-]+++[>+++++<-]>.`,
+		},
+
+		"EOF minus one": {
+			code:   `,.`,
+			policy: brainfuck.EOFMinusOne,
+		},
+
+		"EOF no change": {
+			code:   `++,.`,
+			policy: brainfuck.EOFNoChange,
+		},
+
+		"EOF error": {
+			code:  `,.,.`,
+			input: "a",
+		},
+
+		"out of boundary": {
+			code: `+.<`,
+		},
+
+		"out of boundary right": {
+			code: `>>+>>.>`,
+		},
+
+		"cancelled moves at left edge": {
+			code: `<>+.`,
+		},
+
+		"cancelled moves": {
+			code: `><+.`,
+		},
+
+		"moves back": {
+			code: `>>><<+.`,
+		},
+
+		"folded moves out of boundary right": {
+			code: `+.>>>>>>`,
+		},
+
+		"folded moves out of boundary left": {
+			code: `>><<<`,
+		},
+
+		"separated moves out of boundary": {
+			code: `>> >>>`,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			opts := codegen.DefaultOptions()
+			opts.TapeSize = 5
+			opts.EOFPolicy = test.policy
+
+			t.Run("uint8", func(t *testing.T) {
+				t.Parallel()
+				compare[uint8](t, test.code, test.input, opts)
+			})
+
+			t.Run("int16", func(t *testing.T) {
+				t.Parallel()
+
+				opts := opts
+				opts.CellBits = 16
+				opts.Signed = true
+				compare[int16](t, test.code, test.input, opts)
+			})
+
+			t.Run("int64", func(t *testing.T) {
+				t.Parallel()
+
+				opts := opts
+				opts.CellBits = 64
+				opts.Signed = true
+				compare[int64](t, test.code, test.input, opts)
+			})
+
+			t.Run("uint32", func(t *testing.T) {
+				t.Parallel()
+
+				opts := opts
+				opts.CellBits = 32
+				compare[uint32](t, test.code, test.input, opts)
+			})
+		})
+	}
+}
+
+// compare runs the program with the interpreter and as a module and compares the results
+func compare[DataType constraints.Integer](t *testing.T, code, input string, opts codegen.Options) {
+	t.Helper()
+
+	expOutput := writer.BuildSliceWriter[DataType]()
+	bf := brainfuck.New[DataType](opts.TapeSize, reader.BuildStringReader[DataType](input), expOutput).
+		WithoutPrompt().
+		WithEOFPolicy(opts.EOFPolicy)
+
+	expData, expErr := bf.Run(strings.NewReader(code))
+
+	prog, err := ir.ParseString(code)
+	require.NoError(t, err)
+
+	var module bytes.Buffer
+	require.NoError(t, Generate(&module, ir.Optimize(prog), opts))
+
+	output := writer.BuildSliceWriter[DataType]()
+	data, err := runModule[DataType](t, module.Bytes(), opts.TapeSize, reader.BuildStringReader[DataType](input), output)
+
+	require.Equal(t, expOutput.Values(), output.Values())
+	require.Equal(t, expData, data)
+
+	if expErr == nil {
+		require.NoError(t, err)
+		return
+	}
+
+	require.EqualError(t, err, expErr.Error())
+}
+
+func TestGenerate_OutputError(t *testing.T) {
+	t.Parallel()
+
+	_, expErr := brainfuck.New[uint8](brainfuck.DefaultDataSize, nil, &failingWriter[uint8]{limit: 3}).
+		Run(strings.NewReader(helloWorld))
+
+	prog, err := ir.ParseString(helloWorld)
+	require.NoError(t, err)
+
+	var module bytes.Buffer
+	require.NoError(t, Generate(&module, ir.Optimize(prog), codegen.DefaultOptions()))
+
+	_, err = runModule[uint8](t, module.Bytes(), brainfuck.DefaultDataSize, nil, &failingWriter[uint8]{limit: 3})
+	require.EqualError(t, err, expErr.Error())
+}
+
+func TestGenerateText(t *testing.T) {
+	t.Parallel()
+
+	prog, err := ir.ParseString(`+[>,.<-]`)
+	require.NoError(t, err)
+
+	opts := codegen.DefaultOptions()
+	opts.EOFPolicy = brainfuck.EOFZero
+
+	var wat bytes.Buffer
+	require.NoError(t, GenerateText(&wat, ir.Optimize(prog), opts))
+
+	for _, line := range []string{
+		"(module\n",
+		`  (import "bf" "input" (func $input (param i32 i32) (result i64 i32)))` + "\n",
+		`  (import "bf" "output" (func $output (param i32 i64) (result i32)))` + "\n",
+		`  (memory (export "memory") 1)` + "\n",
+		`  (func $run (export "run") (result i32 i32)` + "\n",
+		"    (local $p i32) (local $s i32) (local $v i64)\n",
+		"    block\n",
+		"      loop\n",
+		"        call $input\n",
+		"        i32.load8_u\n",
+		"        i64.extend_i32_u\n",
+	} {
+		require.Contains(t, wat.String(), line)
+	}
+
+	require.True(t, strings.HasSuffix(wat.String(), "    i32.const 0\n    i32.const 0\n  )\n)\n"))
+}
+
+func TestGenerate_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := codegen.DefaultOptions()
+	opts.CellBits = 64
+	opts.TapeSize = 1 << 29
+
+	require.Error(t, Generate(&bytes.Buffer{}, nil, opts))
+}
+
+func TestGenerate_MaxTape(t *testing.T) {
+	t.Parallel()
+
+	prog, err := ir.ParseString(`>+<`)
+	require.NoError(t, err)
+
+	opts := codegen.DefaultOptions()
+	opts.TapeSize = maxTapeBytes
+
+	var module bytes.Buffer
+	require.NoError(t, Generate(&module, prog, opts))
+
+	// compiling validates the module without allocating the tape
+	ctx := context.Background()
+
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+
+	_, err = r.CompileModule(ctx, module.Bytes())
+	require.NoError(t, err)
+
+	// one byte over the limit, the size still fits in int on 32-bit targets
+	opts.CellBits = 16
+	opts.TapeSize = maxTapeBytes/2 + 1
+	require.Error(t, Generate(&bytes.Buffer{}, prog, opts))
+}
+
+func TestGenerate_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		prog ir.Program
+		opts func(o *codegen.Options)
+	}
+
+	tests := map[string]Test{
+		"tape": {
+			opts: func(o *codegen.Options) {
+				o.CellBits = 16
+				o.TapeSize = maxTapeBytes/2 + 1
+			},
+		},
+
+		"move": {
+			prog: ir.Program{{Op: ir.OpMove, Arg: 1 << 29}},
+			opts: func(o *codegen.Options) { o.CellBits = 64 },
+		},
+
+		"command offset": {
+			prog: ir.Program{{Op: ir.OpMove, Arg: 2, Offset: math.MaxInt32 - 2}},
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			opts := codegen.DefaultOptions()
+			if test.opts != nil {
+				test.opts(&opts)
+			}
+
+			require.Error(t, Generate(&bytes.Buffer{}, test.prog, opts))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package brainfuck
+
+import "fmt"
+
+// Control-flow extension API.
+//
+// Commands are read from the stream one by one, so a handler can't simply jump to an arbitrary command.
+// Control frames solve it: while there's at least one frame every read command is cached,
+// so a handler may jump back to any command after the frame start by setting CmdPtr.
+// Forward jumps are done with ScanForward and JumpToMatching that read commands until the target one.
+//
+// Built-in loops ('[' and ']') use the same frames, so custom constructs must be properly nested with them.
+//
+// Here is a do-while loop that runs its body at least once:
+//
+//	bf.WithCmd('{', func(bf *BfInterpreter[int]) error {
+//		bf.PushFrame(bf.CmdPtr)
+//		return nil
+//	})
+//
+//	bf.WithCmd('}', func(bf *BfInterpreter[int]) error {
+//		start, ok := bf.TopFrame()
+//		if !ok {
+//			return fmt.Errorf("no frame to close")
+//		}
+//
+//		if bf.Cell() != 0 {
+//			bf.CmdPtr = start // the next command is the first one of the body
+//			return nil
+//		}
+//
+//		_, _ = bf.PopFrame()
+//		return nil
+//	})
+
+// PushFrame opens a control frame that starts at the command start.
+// Commands are cached until the frame is popped, so handlers may jump back into it.
+func (bf *BfInterpreter[DataType]) PushFrame(start CmdPtrType) {
+	bf.loopStack.Push(start)
+}
+
+// PopFrame closes the topmost control frame and returns its start. ok is false if there are no frames.
+func (bf *BfInterpreter[DataType]) PopFrame() (start CmdPtrType, ok bool) {
+	v := bf.loopStack.Pop()
+	if v == nil {
+		return 0, false
+	}
+
+	return *v, true
+}
+
+// TopFrame returns the start of the topmost control frame. ok is false if there are no frames.
+func (bf *BfInterpreter[DataType]) TopFrame() (start CmdPtrType, ok bool) {
+	v := bf.loopStack.Get()
+	if v == nil {
+		return 0, false
+	}
+
+	return *v, true
+}
+
+// RetainCommands keeps every read command cached regardless of control frames,
+// so handlers may jump back to any command, i.e. to call procedures that were defined earlier.
+// Memory consumption grows with the size of the program.
+func (bf *BfInterpreter[DataType]) RetainCommands() {
+	bf.retainCmds = true
+}
+
+// OnReset registers a function that is called when interpreter state is reset:
+// at the start of every Run and by Reset. Extensions use it to clear their own state.
+func (bf *BfInterpreter[DataType]) OnReset(f func()) {
+	bf.resetHooks = append(bf.resetHooks, f)
+}
+
+// OnFinish registers a function that is called when Run ends, successfully or not, before the tape is returned
+// and a PointerTape saves the pointer. Extensions use it to restore the state that Run returns.
+func (bf *BfInterpreter[DataType]) OnFinish(f func()) {
+	bf.finishHooks = append(bf.finishHooks, f)
+}
+
+// ScanForward calls f for every command after CmdPtr until f returns false.
+// Commands are read from the stream if necessary and cached if there are open frames.
+// It returns an error if commands are over before f stops scanning.
+func (bf *BfInterpreter[DataType]) ScanForward(f func(ptr CmdPtrType, cmd CmdType) bool) error {
+	for ptr := bf.CmdPtr + 1; ; ptr++ {
+		cmd, ok, err := bf.fetchCmd(ptr)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("unexpected end of commands after [#cmd: %d]", bf.CmdPtr)
+		}
+
+		if !f(ptr, cmd) {
+			return nil
+		}
+	}
+}
+
+// JumpToMatching moves CmdPtr to the close command that matches the open one at CmdPtr, considering nesting.
+// The next executed command will be the one after close.
+func (bf *BfInterpreter[DataType]) JumpToMatching(open, close CmdType) error {
+	depth := 1
+	target := bf.CmdPtr
+
+	err := bf.ScanForward(func(ptr CmdPtrType, cmd CmdType) bool {
+		switch cmd {
+		case open:
+			depth++
+		case close:
+			depth--
+		}
+
+		target = ptr
+		return depth > 0
+	})
+	if err != nil {
+		return fmt.Errorf("no matching '%c' for '%c': %w", close, open, err)
+	}
+
+	bf.CmdPtr = target // bf.CmdPtr will be incremented
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package brainfuck
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/sourcemap"
+	"github.com/yurii-vyrovyi/brainfuck/tapeview"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Optional commands that are common for brainfuck implementations.
+const (
+	// CmdDump writes a tape snapshot to a debug writer
+	CmdDump = CmdType('#')
+
+	// CmdStop separates the program text from its input (as in "prog!input")
+	CmdStop = CmdType('!')
+)
+
+// EnableDumpCmd enables Dump ('#') command that writes a tape snapshot to w:
+// the position of the command and the cells around the data pointer.
+// The source position of the command is added when a source map is set with WithSourceMap.
+func EnableDumpCmd[DataType constraints.Integer](bf *BfInterpreter[DataType], w io.Writer) *BfInterpreter[DataType] {
+	return EnableDumpCmdFormat(bf, w, tapeview.FormatWindow)
+}
+
+// EnableDumpCmdFormat enables Dump ('#') command like EnableDumpCmd does, but renders the tape in the given format.
+// FormatWindow shows the cells around the data pointer and works with any Tape,
+// other formats show the whole tape and need it to be a slice, i.e. Data or SliceTape.
+func EnableDumpCmdFormat[DataType constraints.Integer](
+	bf *BfInterpreter[DataType],
+	w io.Writer,
+	format tapeview.Format,
+) *BfInterpreter[DataType] {
+	return bf.WithCmd(CmdDump, func(bf *BfInterpreter[DataType]) error {
+		cmdPos := fmt.Sprintf("#cmd: %d", bf.CmdPtr)
+		if pos, ok := bf.SourcePosition(); ok {
+			cmdPos += fmt.Sprintf(" (%s)", pos)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s, ptr: %d\n", cmdPos, bf.DataPtr); err != nil {
+			return fmt.Errorf("failed to write dump: %w", err)
+		}
+
+		if err := dumpTape(bf, w, format); err != nil {
+			return fmt.Errorf("failed to write dump: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// dumpTape renders the tape for Dump command
+func dumpTape[DataType constraints.Integer](bf *BfInterpreter[DataType], w io.Writer, format tapeview.Format) error {
+	if format != tapeview.FormatWindow {
+		data := bf.tapeData()
+		if data == nil {
+			return fmt.Errorf("%s format needs a slice tape", format)
+		}
+
+		return tapeview.Render(w, format, data, int(bf.DataPtr))
+	}
+
+	// cells are collected one by one, so the window works with tapes that can't be represented as a slice
+	from := bf.DataPtr - tapeview.DefaultRadius
+	if from < 0 {
+		from = 0
+	}
+
+	to := bf.DataPtr + tapeview.DefaultRadius + 1
+	if to > bf.TapeLen() {
+		to = bf.TapeLen()
+	}
+
+	cells := make([]DataType, 0, to-from)
+	for ptr := from; ptr < to; ptr++ {
+		cells = append(cells, bf.cellAt(ptr))
+	}
+
+	return tapeview.Segment(w, cells, int(from), int(bf.DataPtr))
+}
+
+// TraceEvent describes a command that is about to be processed
+type TraceEvent[DataType any] struct {
+	CmdPtr CmdPtrType
+	Cmd    CmdType
+
+	// Position is the source position of the command. It's nil if there's no source map or it has no position.
+	Position *sourcemap.Position
+
+	DataPtr DataPtrType
+
+	// Value is the current cell before the command
+	Value DataType
+}
+
+// Trace returns a middleware that calls f before every command that has a handler:
+//
+//	bf.Use(Trace(func(e TraceEvent[uint8]) { ... }))
+func Trace[DataType any](f func(TraceEvent[DataType])) Middleware[DataType] {
+	return func(next OpFunc[DataType]) OpFunc[DataType] {
+		return func(bf *BfInterpreter[DataType]) error {
+			e := TraceEvent[DataType]{
+				CmdPtr:  bf.CmdPtr,
+				Cmd:     bf.CurrentCmd(),
+				DataPtr: bf.DataPtr,
+				Value:   bf.Cell(),
+			}
+
+			if pos, ok := bf.SourcePosition(); ok {
+				e.Position = &pos
+			}
+
+			f(e)
+
+			return next(bf)
+		}
+	}
+}
+
+// TraceTo returns a middleware that writes every command to w like Dump ('#') command writes its position:
+// "#cmd: 4 (main.b:1:5) '+', ptr: 1, cell: 2". A command isn't processed if its trace can't be written.
+func TraceTo[DataType any](w io.Writer) Middleware[DataType] {
+	return func(next OpFunc[DataType]) OpFunc[DataType] {
+		return func(bf *BfInterpreter[DataType]) error {
+			cmdPos := fmt.Sprintf("#cmd: %d", bf.CmdPtr)
+			if pos, ok := bf.SourcePosition(); ok {
+				cmdPos += fmt.Sprintf(" (%s)", pos)
+			}
+
+			if _, err := fmt.Fprintf(w, "%s %q, ptr: %d, cell: %v\n", cmdPos, bf.CurrentCmd(), bf.DataPtr, bf.Cell()); err != nil {
+				return fmt.Errorf("failed to write trace: %w", err)
+			}
+
+			return next(bf)
+		}
+	}
+}
+
+// EnableStopCmd enables Stop ('!') command. The program ends on the first '!' and the rest of commands stream
+// is used as Input, so a program and its data may be passed together: ",[.,]!hello".
+// Input is replaced for the time of Run and is restored when Run returns.
+func EnableStopCmd[DataType constraints.Integer](bf *BfInterpreter[DataType]) *BfInterpreter[DataType] {
+	bf.inputFromCommands = func(r io.Reader) InputReader[DataType] {
+		return reader.BuildStreamReader[DataType](r)
+	}
+
+	return bf
+}
+
+// splitStream splits a stream into a program and its input by a separator.
+// The input part may be read before the program is over, in this case the rest of the program is buffered.
+type splitStream struct {
+	src *bufio.Reader
+	sep byte
+
+	// program is the part of the program that was read ahead by the input side
+	program []byte
+
+	// separated is set when the separator or the end of the stream is reached
+	separated bool
+}
+
+// splitCommands returns readers of the program and of the input
+func splitCommands(r io.Reader, sep CmdType) (io.Reader, io.Reader) {
+	s := &splitStream{
+		src: bufio.NewReader(r),
+		sep: byte(sep),
+	}
+
+	return programReader{s}, inputReader{s}
+}
+
+type programReader struct {
+	s *splitStream
+}
+
+func (r programReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(r.s.program) > 0 {
+		n := copy(p, r.s.program)
+		r.s.program = r.s.program[n:]
+		return n, nil
+	}
+
+	if r.s.separated {
+		return 0, io.EOF
+	}
+
+	b, err := r.s.src.ReadByte()
+	if err != nil {
+		r.s.separated = errors.Is(err, io.EOF)
+		return 0, err
+	}
+
+	if b == r.s.sep {
+		r.s.separated = true
+		return 0, io.EOF
+	}
+
+	p[0] = b
+
+	return 1, nil
+}
+
+type inputReader struct {
+	s *splitStream
+}
+
+func (r inputReader) Read(p []byte) (int, error) {
+	for !r.s.separated {
+		b, err := r.s.src.ReadByte()
+		if errors.Is(err, io.EOF) {
+			r.s.separated = true
+			break
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if b == r.s.sep {
+			r.s.separated = true
+			break
+		}
+
+		r.s.program = append(r.s.program, b)
+	}
+
+	return r.s.src.Read(p)
+}
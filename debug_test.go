@@ -0,0 +1,163 @@
+package brainfuck
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/tapeview"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableDumpCmd(t *testing.T) {
+	t.Parallel()
+
+	var dump bytes.Buffer
+
+	bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]())
+	EnableDumpCmd(bf, &dump)
+
+	_, err := bf.Run(strings.NewReader(`+>++#`))
+	require.NoError(t, err)
+
+	require.Equal(t, "#cmd: 4, ptr: 1\n"+
+		"[0]  1 2 0\n"+
+		"       ^\n", dump.String())
+}
+
+func TestEnableDumpCmdFormat(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		format tapeview.Format
+		tape   Tape[TestDataType]
+
+		expErr  bool
+		expDump string
+	}
+
+	tests := map[string]Test{
+		"window": {
+			format:  tapeview.FormatWindow,
+			expDump: "#cmd: 4, ptr: 1\n[0]  1 2 0\n       ^\n",
+		},
+
+		"json": {
+			format:  tapeview.FormatJSON,
+			expDump: "#cmd: 4, ptr: 1\n[1,2]\n",
+		},
+
+		"csv": {
+			format:  tapeview.FormatCSV,
+			expDump: "#cmd: 4, ptr: 1\nindex,value\n0,1\n1,2\n",
+		},
+
+		"window of a sparse tape": {
+			format:  tapeview.FormatWindow,
+			tape:    BuildSparseTape[TestDataType](3),
+			expDump: "#cmd: 4, ptr: 1\n[0]  1 2 0\n       ^\n",
+		},
+
+		"json of a sparse tape": {
+			format: tapeview.FormatJSON,
+			tape:   BuildSparseTape[TestDataType](3),
+			expErr: true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var dump bytes.Buffer
+
+			bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]())
+			if test.tape != nil {
+				bf.WithTape(test.tape)
+			}
+
+			EnableDumpCmdFormat(bf, &dump, test.format)
+
+			_, err := bf.Run(strings.NewReader(`+>++#`))
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expDump, dump.String())
+		})
+	}
+}
+
+func TestEnableStopCmd(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		srcCommands string
+
+		expOutput string
+	}
+
+	tests := map[string]Test{
+		"echo": {
+			srcCommands: `,[.,]!hello`,
+			expOutput:   "hello",
+		},
+
+		"input is read before the program is over": {
+			srcCommands: `,.,.>+++[<.>-]!ab`,
+			expOutput:   "abbbb",
+		},
+
+		"no separator": {
+			srcCommands: `,[.,]`,
+			expOutput:   "",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[TestDataType]()
+
+			bf := New[TestDataType](3, nil, output).WithEOFPolicy(EOFZero)
+			EnableStopCmd(bf)
+
+			_, err := bf.Run(strings.NewReader(test.srcCommands))
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, output.String())
+		})
+	}
+}
+
+func TestEnableStopCmd_RestoresInput(t *testing.T) {
+	t.Parallel()
+
+	input := reader.BuildSliceReader[TestDataType]()
+	output := writer.BuildSliceWriter[TestDataType]()
+
+	bf := New[TestDataType](3, input, output)
+	EnableStopCmd(bf)
+
+	_, err := bf.Run(strings.NewReader(`,.!a`))
+	require.NoError(t, err)
+	require.Same(t, input, bf.Input)
+
+	// the input of the previous run isn't reused
+	_, err = bf.Run(strings.NewReader(`,.!b`))
+	require.NoError(t, err)
+	require.Same(t, input, bf.Input)
+
+	require.Equal(t, "ab", output.String())
+}
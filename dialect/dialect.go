@@ -0,0 +1,170 @@
+// Package dialect translates BF-equivalent languages with multi-character tokens (Ook!, Blub, Spoon etc.)
+// to brainfuck commands. Reader is a tokenizer layer between the source and BfInterpreter.Run:
+//
+//	src, err := dialect.NewReader(file, dialect.Ook)
+//	...
+//	data, err := bf.Run(src)
+//
+// Whitespaces are ignored, so tokens may be split between lines. Text that doesn't form any token is skipped as a comment.
+// When several tokens match, the longest one wins.
+package dialect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Dialect maps tokens of a language to brainfuck commands.
+type Dialect struct {
+	Name string `json:"name"`
+
+	// Tokens maps a token to a brainfuck command (a string of one byte). Whitespaces in tokens are ignored.
+	Tokens map[string]string `json:"tokens"`
+}
+
+var (
+	// Ook is Ook! language
+	Ook = Dialect{
+		Name: "ook",
+		Tokens: map[string]string{
+			"Ook. Ook?": ">",
+			"Ook? Ook.": "<",
+			"Ook. Ook.": "+",
+			"Ook! Ook!": "-",
+			"Ook! Ook.": ".",
+			"Ook. Ook!": ",",
+			"Ook! Ook?": "[",
+			"Ook? Ook!": "]",
+		},
+	}
+
+	// Blub is Blub language, it's Ook! with different words
+	Blub = Dialect{
+		Name: "blub",
+		Tokens: map[string]string{
+			"Blub. Blub?": ">",
+			"Blub? Blub.": "<",
+			"Blub. Blub.": "+",
+			"Blub! Blub!": "-",
+			"Blub! Blub.": ".",
+			"Blub. Blub!": ",",
+			"Blub! Blub?": "[",
+			"Blub? Blub!": "]",
+		},
+	}
+
+	// Spoon is Spoon language that uses Huffman-coded binary tokens
+	Spoon = Dialect{
+		Name: "spoon",
+		Tokens: map[string]string{
+			"1":       "+",
+			"000":     "-",
+			"010":     ">",
+			"011":     "<",
+			"00100":   "[",
+			"0011":    "]",
+			"001010":  ".",
+			"0010110": ",",
+		},
+	}
+)
+
+// Builtin returns a built-in dialect by its name
+func Builtin(name string) (Dialect, bool) {
+	for _, d := range []Dialect{Ook, Blub, Spoon} {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+
+	return Dialect{}, false
+}
+
+// Load reads a dialect from JSON config:
+//
+//	{
+//	  "name": "my-dialect",
+//	  "tokens": {"inc": "+", "dec": "-", ...}
+//	}
+func Load(r io.Reader) (Dialect, error) {
+	var d Dialect
+
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return Dialect{}, fmt.Errorf("failed to decode dialect: %w", err)
+	}
+
+	if _, err := d.compile(); err != nil {
+		return Dialect{}, err
+	}
+
+	return d, nil
+}
+
+// LoadFile reads a dialect from JSON config file. See Load for the format.
+func LoadFile(fileName string) (Dialect, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return Dialect{}, fmt.Errorf("failed to open dialect file: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// tokenTable is a dialect prepared for tokenizing
+type tokenTable struct {
+
+	// tokens maps normalized tokens to commands
+	tokens map[string]byte
+
+	// prefixes contains all prefixes of all tokens including tokens themselves
+	prefixes map[string]bool
+}
+
+// compile validates the dialect and builds tokenTable
+func (d Dialect) compile() (*tokenTable, error) {
+	if len(d.Tokens) == 0 {
+		return nil, fmt.Errorf("dialect %s has no tokens", d.Name)
+	}
+
+	t := tokenTable{
+		tokens:   make(map[string]byte, len(d.Tokens)),
+		prefixes: make(map[string]bool),
+	}
+
+	for token, cmd := range d.Tokens {
+		if len(cmd) != 1 {
+			return nil, fmt.Errorf("dialect %s: token %q must map to a single command, got %q", d.Name, token, cmd)
+		}
+
+		norm := removeSpaces(token)
+		if norm == "" {
+			return nil, fmt.Errorf("dialect %s: empty token for %q", d.Name, cmd)
+		}
+
+		if _, ok := t.tokens[norm]; ok {
+			return nil, fmt.Errorf("dialect %s: duplicated token %q", d.Name, token)
+		}
+
+		t.tokens[norm] = cmd[0]
+
+		for i := 1; i <= len(norm); i++ {
+			t.prefixes[norm[:i]] = true
+		}
+	}
+
+	return &t, nil
+}
+
+func removeSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,99 @@
+package dialect
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		dialect Dialect
+		src     string
+
+		expCommands string
+	}
+
+	tests := map[string]Test{
+		"Ook": {
+			dialect: Ook,
+			src: "Ook. Ook. Ook! Ook?\n" +
+				"Ook. Ook? Ook. Ook.   Ook? Ook. Ook! Ook!\n" +
+				"Ook? Ook! Ook. Ook? Ook! Ook.",
+			expCommands: "+[>+<-]>.",
+		},
+
+		"Ook with comments": {
+			dialect:     Ook,
+			src:         "increment: Ook. Ook. Ook, print it: Ook! Ook.",
+			expCommands: "+.",
+		},
+
+		"Blub": {
+			dialect:     Blub,
+			src:         "Blub. Blub. Blub! Blub.",
+			expCommands: "+.",
+		},
+
+		"Spoon": {
+			dialect:     Spoon,
+			src:         "1 1 00100 010 1 011 000 0011 010 001010",
+			expCommands: "++[>+<-]>.",
+		},
+
+		"Custom with overlapping tokens": {
+			dialect: Dialect{
+				Name: "overlap",
+				Tokens: map[string]string{
+					"a":  "+",
+					"aa": "-",
+					"b":  ".",
+				},
+			},
+			src:         "aaab a",
+			expCommands: "-+.+",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewReader(strings.NewReader(test.src), test.dialect)
+			require.NoError(t, err)
+
+			commands, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, test.expCommands, string(commands))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	d, err := Load(strings.NewReader(`{"name": "words", "tokens": {"inc": "+", "out": "."}}`))
+	require.NoError(t, err)
+	require.Equal(t, "words", d.Name)
+
+	r, err := NewReader(strings.NewReader("inc inc out"), d)
+	require.NoError(t, err)
+
+	commands, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "++.", string(commands))
+
+	_, err = Load(strings.NewReader(`{"name": "bad", "tokens": {"inc": "++"}}`))
+	require.Error(t, err)
+
+	spoon, ok := Builtin("Spoon")
+	require.True(t, ok)
+	require.Equal(t, Spoon.Name, spoon.Name)
+}
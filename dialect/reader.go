@@ -0,0 +1,103 @@
+package dialect
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"unicode"
+)
+
+// Reader implements io.Reader that translates dialect tokens to brainfuck commands.
+// It reads the source lazily, so commands are available as soon as their tokens are read.
+type Reader struct {
+	src   *bufio.Reader
+	table *tokenTable
+
+	// pending is the source text that is not tokenized yet
+	pending []byte
+
+	// out is translated commands that were not read yet
+	out []byte
+
+	// eof is set when the source is over
+	eof bool
+}
+
+// NewReader creates Reader instance that translates src according to the dialect.
+func NewReader(src io.Reader, d Dialect) (*Reader, error) {
+	table, err := d.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		src:   bufio.NewReader(src),
+		table: table,
+	}, nil
+}
+
+// Read fills p with brainfuck commands
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.eof {
+			r.tokenize(true)
+
+			if len(r.out) == 0 {
+				return 0, io.EOF
+			}
+
+			break
+		}
+
+		c, err := r.src.ReadByte()
+		if errors.Is(err, io.EOF) {
+			r.eof = true
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if c < unicode.MaxASCII && unicode.IsSpace(rune(c)) {
+			continue
+		}
+
+		r.pending = append(r.pending, c)
+		r.tokenize(false)
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+
+	return n, nil
+}
+
+// tokenize moves complete tokens from pending to out.
+// Pending text waits for more input while it's a prefix of some token, unless it's the final call.
+// Text that can't be a start of a token is dropped byte per byte as a comment.
+func (r *Reader) tokenize(final bool) {
+	for len(r.pending) > 0 {
+
+		// the longest token that starts the pending text
+		match := 0
+		for i := 1; i <= len(r.pending); i++ {
+			if _, ok := r.table.tokens[string(r.pending[:i])]; ok {
+				match = i
+			}
+		}
+
+		// a longer token is still possible
+		if !final && r.table.prefixes[string(r.pending)] {
+			return
+		}
+
+		if match == 0 {
+			r.pending = r.pending[1:]
+			continue
+		}
+
+		r.out = append(r.out, r.table.tokens[string(r.pending[:match])])
+		r.pending = r.pending[match:]
+	}
+}
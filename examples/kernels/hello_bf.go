@@ -0,0 +1,168 @@
+// Code generated by bf gen-go. DO NOT EDIT.
+
+package kernels
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+)
+
+// Hello runs the brainfuck program with 4096 cells of int32 type.
+func Hello(in brainfuck.InputReader[int32], out brainfuck.OutputWriter[int32]) ([]int32, error) {
+	data := make([]int32, 4096)
+	p := 0
+
+	write := func(cmdPtr brainfuck.CmdPtrType) error {
+		if err := out.Write(data[p]); err != nil {
+			return &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '.', Err: fmt.Errorf("failed to write value: %w", err)}
+		}
+		return nil
+	}
+
+	data[p] += 8
+	for data[p] != 0 {
+		if p+1 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: 9, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p++
+		data[p] += 4
+		for data[p] != 0 {
+			if p+1 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: 15, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p++
+			data[p] += 2
+			if p+1 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: 18, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p++
+			data[p] += 3
+			if p+1 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: 22, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p++
+			data[p] += 3
+			if p+1 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: 26, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p++
+			data[p]++
+			if p < 4 {
+				return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(28 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p -= 4
+			data[p]--
+		}
+		if p+1 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: 34, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p++
+		data[p]++
+		if p+1 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: 36, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p++
+		data[p]++
+		if p+1 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: 38, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p++
+		data[p]--
+		if p+2 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(40 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p += 2
+		data[p]++
+		for data[p] != 0 {
+			if p < 1 {
+				return nil, &brainfuck.CmdError{CmdPtr: 44, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p--
+		}
+		if p < 1 {
+			return nil, &brainfuck.CmdError{CmdPtr: 46, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+		}
+		p--
+		data[p]--
+	}
+	if p+2 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(49 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p += 2
+	if err := write(51); err != nil {
+		return nil, err
+	}
+	if p+1 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: 52, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p++
+	data[p] += -3
+	if err := write(56); err != nil {
+		return nil, err
+	}
+	data[p] += 7
+	if err := write(64); err != nil {
+		return nil, err
+	}
+	if err := write(65); err != nil {
+		return nil, err
+	}
+	data[p] += 3
+	if err := write(69); err != nil {
+		return nil, err
+	}
+	if p+2 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(70 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p += 2
+	if err := write(72); err != nil {
+		return nil, err
+	}
+	if p < 1 {
+		return nil, &brainfuck.CmdError{CmdPtr: 73, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+	}
+	p--
+	data[p]--
+	if err := write(75); err != nil {
+		return nil, err
+	}
+	if p < 1 {
+		return nil, &brainfuck.CmdError{CmdPtr: 76, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+	}
+	p--
+	if err := write(77); err != nil {
+		return nil, err
+	}
+	data[p] += 3
+	if err := write(81); err != nil {
+		return nil, err
+	}
+	data[p] += -6
+	if err := write(88); err != nil {
+		return nil, err
+	}
+	data[p] += -8
+	if err := write(97); err != nil {
+		return nil, err
+	}
+	if p+2 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(98 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p += 2
+	data[p]++
+	if err := write(101); err != nil {
+		return nil, err
+	}
+	if p+1 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: 102, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p++
+	data[p] += 2
+	if err := write(105); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
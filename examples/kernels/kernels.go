@@ -0,0 +1,7 @@
+// Package kernels shows how brainfuck programs are compiled into Go code with go:generate.
+// Run "go generate ./..." after changing *.b files.
+package kernels
+
+//go:generate go run github.com/yurii-vyrovyi/brainfuck/cmd/bf gen-go -func Rot13 -eof=no-change -tape=64 -o rot13_bf.go rot13.b
+//go:generate go run github.com/yurii-vyrovyi/brainfuck/cmd/bf gen-go -func Hello -cell=32 -signed -no-prompt -o hello_bf.go hello.b
+//go:generate go run github.com/yurii-vyrovyi/brainfuck/cmd/bf gen-go -func Overflow -tape=4 -o overflow_bf.go overflow.b
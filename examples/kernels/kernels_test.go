@@ -0,0 +1,119 @@
+package kernels
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails after limit values are written
+type failingWriter[DataType any] struct {
+	limit int
+}
+
+func (w *failingWriter[DataType]) Write(DataType) error {
+	if w.limit == 0 {
+		return errors.New("sink is full")
+	}
+
+	w.limit--
+
+	return nil
+}
+
+func (w *failingWriter[DataType]) Close() error {
+	return nil
+}
+
+// TestRot13 compares the generated function with the interpreter
+func TestRot13(t *testing.T) {
+	t.Parallel()
+
+	code, err := os.ReadFile("rot13.b")
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"empty":   "",
+		"letters": "Hello, World!",
+		"all":     "abcdefghijklmnopqrstuvwxyz ABCDEFGHIJKLMNOPQRSTUVWXYZ 0123456789",
+	}
+
+	//nolint:paralleltest
+	for description, input := range tests {
+		input := input
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			expOutput := writer.BuildSliceWriter[uint8]()
+			bf := brainfuck.New[uint8](64, reader.BuildStringReader[uint8](input), expOutput).
+				WithEOFPolicy(brainfuck.EOFNoChange)
+
+			expData, expErr := bf.Run(bytes.NewReader(code))
+			require.NoError(t, expErr)
+
+			output := writer.BuildSliceWriter[uint8]()
+			data, err := Rot13(reader.BuildStringReader[uint8](input), output)
+			require.NoError(t, err)
+
+			require.Equal(t, expOutput.String(), output.String())
+			require.Equal(t, expData, data)
+		})
+	}
+}
+
+// TestHello compares the generated function with the interpreter including errors
+func TestHello(t *testing.T) {
+	t.Parallel()
+
+	code, err := os.ReadFile("hello.b")
+	require.NoError(t, err)
+
+	output := writer.BuildSliceWriter[int32]()
+	data, err := Hello(nil, output)
+	require.NoError(t, err)
+	require.Equal(t, "Hello World!\n", output.String())
+
+	expData, err := brainfuck.New[int32](brainfuck.DefaultDataSize, nil, writer.BuildSliceWriter[int32]()).
+		Run(bytes.NewReader(code))
+	require.NoError(t, err)
+	require.Equal(t, expData, data)
+
+	// failing output
+	_, expErr := brainfuck.New[int32](brainfuck.DefaultDataSize, nil, &failingWriter[int32]{limit: 3}).
+		Run(bytes.NewReader(code))
+
+	_, err = Hello(nil, &failingWriter[int32]{limit: 3})
+
+	require.EqualError(t, err, expErr.Error())
+
+	var cmdErr *brainfuck.CmdError
+	require.True(t, errors.As(err, &cmdErr))
+	require.Equal(t, expErr.(*brainfuck.CmdError).CmdPtr, cmdErr.CmdPtr)
+}
+
+// TestOverflow checks that a folded move reports the command that leaves the tape
+func TestOverflow(t *testing.T) {
+	t.Parallel()
+
+	code, err := os.ReadFile("overflow.b")
+	require.NoError(t, err)
+
+	_, expErr := brainfuck.New[uint8](4, nil, writer.BuildSliceWriter[uint8]()).
+		Run(bytes.NewReader(code))
+	require.Error(t, expErr)
+
+	_, err = Overflow(nil, writer.BuildSliceWriter[uint8]())
+	require.EqualError(t, err, expErr.Error())
+
+	var cmdErr *brainfuck.CmdError
+	require.True(t, errors.As(err, &cmdErr))
+	require.Equal(t, expErr.(*brainfuck.CmdError).CmdPtr, cmdErr.CmdPtr)
+}
@@ -0,0 +1,2 @@
+Walks past the end of a 4 cell tape: the error points to the fourth move
++>>>>>>
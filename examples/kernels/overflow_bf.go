@@ -0,0 +1,23 @@
+// Code generated by bf gen-go. DO NOT EDIT.
+
+package kernels
+
+import (
+	"errors"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+)
+
+// Overflow runs the brainfuck program with 4 cells of uint8 type.
+func Overflow(in brainfuck.InputReader[uint8], out brainfuck.OutputWriter[uint8]) ([]uint8, error) {
+	data := make([]uint8, 4)
+	p := 0
+
+	data[p]++
+	if p+6 >= len(data) {
+		return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(74 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+	}
+	p += 6
+
+	return data, nil
+}
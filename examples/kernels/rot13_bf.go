@@ -0,0 +1,319 @@
+// Code generated by bf gen-go. DO NOT EDIT.
+
+package kernels
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+)
+
+// Rot13 runs the brainfuck program with 64 cells of uint8 type.
+func Rot13(in brainfuck.InputReader[uint8], out brainfuck.OutputWriter[uint8]) ([]uint8, error) {
+	data := make([]uint8, 64)
+	p := 0
+
+	read := func(cmdPtr brainfuck.CmdPtrType) error {
+		v, err := in.Read(prompt.Hint[uint8]{
+			Text:    brainfuck.DefaultPrompt(cmdPtr, brainfuck.DataPtrType(p), data[p]),
+			CmdPtr:  int(cmdPtr),
+			DataPtr: p,
+			Value:   data[p],
+		})
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: ',', Err: fmt.Errorf("failed to read value: %w", err)}
+		}
+		data[p] = v
+		return nil
+	}
+
+	write := func(cmdPtr brainfuck.CmdPtrType) error {
+		if err := out.Write(data[p]); err != nil {
+			return &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '.', Err: fmt.Errorf("failed to write value: %w", err)}
+		}
+		return nil
+	}
+
+	data[p]--
+	if err := read(1); err != nil {
+		return nil, err
+	}
+	data[p]++
+	for data[p] != 0 {
+		data[p]--
+		for data[p] != 0 {
+			if p+2 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(6 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p += 2
+			data[p] += 4
+			for data[p] != 0 {
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 13, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p] += 8
+				if p < 1 {
+					return nil, &brainfuck.CmdError{CmdPtr: 22, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p--
+				data[p]--
+			}
+			if p < 1 {
+				return nil, &brainfuck.CmdError{CmdPtr: 25, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p--
+			data[p]++
+			if p < 1 {
+				return nil, &brainfuck.CmdError{CmdPtr: 27, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p--
+			data[p]--
+			for data[p] != 0 {
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 30, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p]++
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 32, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p]++
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 34, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p]--
+				for data[p] != 0 {
+					if p+3 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(37 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 3
+				}
+				if p < 1 {
+					return nil, &brainfuck.CmdError{CmdPtr: 41, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p--
+				for data[p] != 0 {
+					for data[p] != 0 {
+						if p+1 >= len(data) {
+							return nil, &brainfuck.CmdError{CmdPtr: 44, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+						}
+						p++
+						data[p]++
+						if p < 1 {
+							return nil, &brainfuck.CmdError{CmdPtr: 46, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+						}
+						p--
+						data[p]--
+					}
+					if p+2 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(49 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 2
+					data[p]++
+					if p+1 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: 52, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p++
+				}
+				if p < 5 {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(54 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p -= 5
+				data[p]--
+			}
+		}
+		if p+3 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(62 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p += 3
+		data[p] = 0
+		data[p]++
+		if p+1 >= len(data) {
+			return nil, &brainfuck.CmdError{CmdPtr: 69, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+		}
+		p++
+		data[p] += 254
+		for data[p] != 0 {
+			data[p]--
+			for data[p] != 0 {
+				if p < 1 {
+					return nil, &brainfuck.CmdError{CmdPtr: 75, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p--
+				data[p]--
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 77, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p] += 3
+				data[p] = 0
+			}
+		}
+		if p < 1 {
+			return nil, &brainfuck.CmdError{CmdPtr: 86, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+		}
+		p--
+		for data[p] != 0 {
+			data[p] += 12
+			if p < 1 {
+				return nil, &brainfuck.CmdError{CmdPtr: 100, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p--
+			for data[p] != 0 {
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 102, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p]--
+				for data[p] != 0 {
+					if p+1 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: 105, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p++
+					data[p]++
+					if p+2 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(107 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 2
+				}
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 110, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				for data[p] != 0 {
+					data[p]++
+					for data[p] != 0 {
+						if p < 1 {
+							return nil, &brainfuck.CmdError{CmdPtr: 114, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+						}
+						p--
+						data[p]++
+						if p+1 >= len(data) {
+							return nil, &brainfuck.CmdError{CmdPtr: 116, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+						}
+						p++
+						data[p]--
+					}
+					if p+1 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: 119, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p++
+					data[p]++
+					if p+2 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(121 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 2
+				}
+				if p < 5 {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(124 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p -= 5
+				data[p]--
+			}
+			if p+2 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(131 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p += 2
+			for data[p] != 0 {
+				if p < 1 {
+					return nil, &brainfuck.CmdError{CmdPtr: 134, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p--
+				data[p]++
+				if p+1 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: 136, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p++
+				data[p]--
+			}
+			if p+1 >= len(data) {
+				return nil, &brainfuck.CmdError{CmdPtr: 139, Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+			}
+			p++
+			for data[p] != 0 {
+				data[p]--
+				for data[p] != 0 {
+					data[p]--
+					if p < 2 {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(144 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+					}
+					p -= 2
+					data[p] = 0
+					if p+2 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(149 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 2
+				}
+				if p < 2 {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(152 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p -= 2
+				for data[p] != 0 {
+					if p < 2 {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(155 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+					}
+					p -= 2
+					data[p]--
+					if p+2 >= len(data) {
+						return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(158 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+					}
+					p += 2
+					data[p]--
+				}
+				if p+2 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(162 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p += 2
+			}
+			if p < 2 {
+				return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(165 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+			}
+			p -= 2
+			for data[p] != 0 {
+				if p < 2 {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(168 + p), Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+				}
+				p -= 2
+				data[p]++
+				if p+2 >= len(data) {
+					return nil, &brainfuck.CmdError{CmdPtr: brainfuck.CmdPtrType(171 + len(data) - 1 - p), Cmd: '>', Err: errors.New("shift+ moves out of boundary")}
+				}
+				p += 2
+				data[p]--
+			}
+		}
+		if p < 1 {
+			return nil, &brainfuck.CmdError{CmdPtr: 176, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+		}
+		p--
+		data[p] = 0
+		if p < 1 {
+			return nil, &brainfuck.CmdError{CmdPtr: 180, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+		}
+		p--
+		if err := write(181); err != nil {
+			return nil, err
+		}
+		data[p] = 0
+		if p < 1 {
+			return nil, &brainfuck.CmdError{CmdPtr: 185, Cmd: '<', Err: errors.New("shift- moves out of boundary")}
+		}
+		p--
+		data[p]--
+		if err := read(187); err != nil {
+			return nil, err
+		}
+		data[p]++
+	}
+
+	return data, nil
+}
@@ -0,0 +1,273 @@
+// Package ir is an intermediate representation of brainfuck programs that is shared by code generators.
+//
+// Parse translates every command to an instruction, Optimize folds runs of commands
+// (+++ to Add 3, >>> to Move 3) and replaces clear loops ([-], [+]) with Clear instruction.
+// Moves are folded only in the same direction and only when commands are adjacent, so an optimized program leaves the
+// tape whenever the source does and the command that leaves it may be computed (see Instr).
+package ir
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OpCode is an instruction code
+type OpCode byte
+
+const (
+	// OpAdd adds Arg to the current cell
+	OpAdd OpCode = iota
+
+	// OpMove moves the data pointer by Arg cells
+	OpMove
+
+	// OpOut writes the current cell to output
+	OpOut
+
+	// OpIn reads the current cell from input
+	OpIn
+
+	// OpLoop starts a loop. Arg is the index of the matching OpEnd.
+	OpLoop
+
+	// OpEnd ends a loop. Arg is the index of the matching OpLoop.
+	OpEnd
+
+	// OpClear sets the current cell to zero
+	OpClear
+)
+
+func (op OpCode) String() string {
+	switch op {
+	case OpAdd:
+		return "add"
+	case OpMove:
+		return "move"
+	case OpOut:
+		return "out"
+	case OpIn:
+		return "in"
+	case OpLoop:
+		return "loop"
+	case OpEnd:
+		return "end"
+	case OpClear:
+		return "clear"
+	default:
+		return fmt.Sprintf("op(%d)", op)
+	}
+}
+
+// Instr is a program instruction
+type Instr struct {
+	Op  OpCode
+	Arg int
+
+	// Offset is the offset of the (first) command of the instruction in the source, i.e. CmdPtr of the interpreter.
+	// Move of n cells stands for |n| adjacent commands, so when it leaves a tape of size cells from the cell p
+	// the failed command is Offset+size-1-p for '>' and Offset+p for '<'.
+	Offset int
+}
+
+// Program is a list of instructions with matched loops
+type Program []Instr
+
+// ErrUnmatchedLoop is returned by Parse when a loop has no matching beginning or end
+var ErrUnmatchedLoop = errors.New("unmatched loop")
+
+// Parse reads brainfuck code. Every command becomes an instruction, other characters are ignored.
+func Parse(r io.Reader) (Program, error) {
+	br := bufio.NewReader(r)
+
+	var prog Program
+
+	for offset := 0; ; offset++ {
+		c, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commands: %w", err)
+		}
+
+		switch c {
+		case '+':
+			prog = append(prog, Instr{Op: OpAdd, Arg: 1, Offset: offset})
+		case '-':
+			prog = append(prog, Instr{Op: OpAdd, Arg: -1, Offset: offset})
+		case '>':
+			prog = append(prog, Instr{Op: OpMove, Arg: 1, Offset: offset})
+		case '<':
+			prog = append(prog, Instr{Op: OpMove, Arg: -1, Offset: offset})
+		case '.':
+			prog = append(prog, Instr{Op: OpOut, Offset: offset})
+		case ',':
+			prog = append(prog, Instr{Op: OpIn, Offset: offset})
+		case '[':
+			prog = append(prog, Instr{Op: OpLoop, Offset: offset})
+		case ']':
+			prog = append(prog, Instr{Op: OpEnd, Offset: offset})
+		}
+	}
+
+	if err := prog.link(); err != nil {
+		return nil, err
+	}
+
+	return prog, nil
+}
+
+// ParseString parses brainfuck code from a string
+func ParseString(code string) (Program, error) {
+	return Parse(strings.NewReader(code))
+}
+
+// Optimize folds runs of Add and Move instructions and replaces clear loops with Clear instruction.
+//
+// Moves in opposite directions are not folded: "<>" at the first cell leaves the tape, and folding it to nothing
+// would lose the error. Moves that are separated by other characters are not folded either, so the offset
+// of every folded command is known.
+func Optimize(prog Program) Program {
+	res := make(Program, 0, len(prog))
+
+	for _, in := range prog {
+		n := len(res)
+
+		switch {
+		case in.Op == OpAdd && n > 0 && res[n-1].Op == OpAdd:
+			res[n-1].Arg += in.Arg
+
+			// dropping runs that cancel out, i.e. "+-"
+			if res[n-1].Arg == 0 {
+				res = res[:n-1]
+			}
+
+		case in.Op == OpMove && n > 0 && res[n-1].Op == OpMove && (res[n-1].Arg < 0) == (in.Arg < 0) &&
+			in.Offset == res[n-1].Offset+abs(res[n-1].Arg):
+			res[n-1].Arg += in.Arg
+
+		case in.Op == OpEnd && n >= 2 && res[n-2].Op == OpLoop && res[n-1].Op == OpAdd && (res[n-1].Arg == 1 || res[n-1].Arg == -1):
+			res = append(res[:n-2], Instr{Op: OpClear, Offset: res[n-2].Offset})
+
+		default:
+			res = append(res, in)
+		}
+	}
+
+	// loops are balanced, so linking can't fail
+	_ = res.link()
+
+	return res
+}
+
+// link sets Arg of loop instructions to the indexes of matching instructions
+func (prog Program) link() error {
+	var loops []int
+
+	for i := range prog {
+		switch prog[i].Op {
+		case OpLoop:
+			loops = append(loops, i)
+
+		case OpEnd:
+			if len(loops) == 0 {
+				return fmt.Errorf("%w [#cmd: %d]", ErrUnmatchedLoop, prog[i].Offset)
+			}
+
+			start := loops[len(loops)-1]
+			loops = loops[:len(loops)-1]
+
+			prog[start].Arg = i
+			prog[i].Arg = start
+		}
+	}
+
+	if len(loops) > 0 {
+		return fmt.Errorf("%w [#cmd: %d]", ErrUnmatchedLoop, prog[loops[len(loops)-1]].Offset)
+	}
+
+	return nil
+}
+
+// HasInput reports whether the program reads input
+func (prog Program) HasInput() bool {
+	for _, in := range prog {
+		if in.Op == OpIn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasOutput reports whether the program writes output
+func (prog Program) HasOutput() bool {
+	for _, in := range prog {
+		if in.Op == OpOut {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Format writes the program as brainfuck code
+func Format(w io.Writer, prog Program) error {
+	var sb strings.Builder
+
+	for _, in := range prog {
+		switch in.Op {
+		case OpAdd:
+			sb.WriteString(repeat('+', '-', in.Arg))
+		case OpMove:
+			sb.WriteString(repeat('>', '<', in.Arg))
+		case OpOut:
+			sb.WriteByte('.')
+		case OpIn:
+			sb.WriteByte(',')
+		case OpLoop:
+			sb.WriteByte('[')
+		case OpEnd:
+			sb.WriteByte(']')
+		case OpClear:
+			sb.WriteString("[-]")
+		default:
+			return fmt.Errorf("unknown instruction: %s", in.Op)
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write program: %w", err)
+	}
+
+	return nil
+}
+
+// String returns the program as brainfuck code
+func (prog Program) String() string {
+	var sb strings.Builder
+	_ = Format(&sb, prog)
+
+	return sb.String()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// repeat returns n times pos character or -n times neg character
+func repeat(pos, neg byte, n int) string {
+	if n < 0 {
+		return strings.Repeat(string(neg), -n)
+	}
+
+	return strings.Repeat(string(pos), n)
+}
@@ -0,0 +1,140 @@
+package ir
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	prog, err := ParseString("+a>[-]<.,")
+	require.NoError(t, err)
+
+	require.Equal(t, Program{
+		{Op: OpAdd, Arg: 1, Offset: 0},
+		{Op: OpMove, Arg: 1, Offset: 2},
+		{Op: OpLoop, Arg: 4, Offset: 3},
+		{Op: OpAdd, Arg: -1, Offset: 4},
+		{Op: OpEnd, Arg: 2, Offset: 5},
+		{Op: OpMove, Arg: -1, Offset: 6},
+		{Op: OpOut, Offset: 7},
+		{Op: OpIn, Offset: 8},
+	}, prog)
+
+	require.True(t, prog.HasInput())
+	require.True(t, prog.HasOutput())
+}
+
+func TestParse_UnmatchedLoop(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"unmatched end":   "+]",
+		"unmatched start": "[[]",
+	}
+
+	//nolint:paralleltest
+	for description, code := range tests {
+		code := code
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseString(code)
+			require.True(t, errors.Is(err, ErrUnmatchedLoop))
+		})
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		code string
+
+		expProg Program
+		expCode string
+	}
+
+	tests := map[string]Test{
+		"runs": {
+			code: "+++>>-<",
+			expProg: Program{
+				{Op: OpAdd, Arg: 3, Offset: 0},
+				{Op: OpMove, Arg: 2, Offset: 3},
+				{Op: OpAdd, Arg: -1, Offset: 5},
+				{Op: OpMove, Arg: -1, Offset: 6},
+			},
+			expCode: "+++>>-<",
+		},
+
+		"cancelled adds": {
+			code:    "+-.",
+			expProg: Program{{Op: OpOut, Offset: 2}},
+			expCode: ".",
+		},
+
+		"opposite moves": {
+			code: ">><<<>.",
+			expProg: Program{
+				{Op: OpMove, Arg: 2, Offset: 0},
+				{Op: OpMove, Arg: -3, Offset: 2},
+				{Op: OpMove, Arg: 1, Offset: 5},
+				{Op: OpOut, Offset: 6},
+			},
+			expCode: ">><<<>.",
+		},
+
+		"separated moves": {
+			code: ">> >.",
+			expProg: Program{
+				{Op: OpMove, Arg: 2, Offset: 0},
+				{Op: OpMove, Arg: 1, Offset: 3},
+				{Op: OpOut, Offset: 4},
+			},
+			expCode: ">>>.",
+		},
+
+		"clear loops": {
+			code: "+[[-]>[+]]",
+			expProg: Program{
+				{Op: OpAdd, Arg: 1, Offset: 0},
+				{Op: OpLoop, Arg: 5, Offset: 1},
+				{Op: OpClear, Offset: 2},
+				{Op: OpMove, Arg: 1, Offset: 5},
+				{Op: OpClear, Offset: 6},
+				{Op: OpEnd, Arg: 1, Offset: 9},
+			},
+			expCode: "+[[-]>[-]]",
+		},
+
+		"not a clear loop": {
+			code: "[--]",
+			expProg: Program{
+				{Op: OpLoop, Arg: 2, Offset: 0},
+				{Op: OpAdd, Arg: -2, Offset: 1},
+				{Op: OpEnd, Arg: 0, Offset: 3},
+			},
+			expCode: "[--]",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			prog, err := ParseString(test.code)
+			require.NoError(t, err)
+
+			opt := Optimize(prog)
+			require.Equal(t, test.expProg, opt)
+			require.Equal(t, test.expCode, opt.String())
+		})
+	}
+}
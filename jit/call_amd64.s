@@ -0,0 +1,16 @@
+//go:build linux && amd64
+
+#include "textflag.h"
+
+// func callJIT(code, mem, ptr, fuel uintptr) (status, newPtr, resume, cmd uintptr)
+TEXT ·callJIT(SB), NOSPLIT, $0-64
+	MOVQ code+0(FP), AX
+	MOVQ mem+8(FP), DI
+	MOVQ ptr+16(FP), SI
+	MOVQ fuel+24(FP), R8
+	CALL AX
+	MOVQ AX, status+32(FP)
+	MOVQ SI, newPtr+40(FP)
+	MOVQ DX, resume+48(FP)
+	MOVQ CX, cmd+56(FP)
+	RET
@@ -0,0 +1,72 @@
+// Package jit runs brainfuck programs compiled to native code.
+//
+// On linux/amd64 a program is compiled to x86-64 machine code in an mmap'd executable page and the tape is mmap'd too.
+// The compiled code returns to Go for input and output and periodically in long loops, so goroutines are still
+// preempted. On other platforms, and for programs with unmatched loops, Run falls back to BfInterpreter.
+//
+// Runner behaves like BfInterpreter created with New and the same EOF policy.
+package jit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Runner runs programs with JIT compilation
+type Runner[DataType constraints.Integer] struct {
+	dataSize  int
+	input     brainfuck.InputReader[DataType]
+	output    brainfuck.OutputWriter[DataType]
+	eofPolicy brainfuck.EOFPolicy
+
+	// fuel is the number of loop iterations before compiled code returns to Go to let the scheduler preempt it
+	fuel uintptr
+}
+
+const defaultFuel = 1 << 20
+
+// New creates Runner instance with a tape of dataSize cells. Like brainfuck.New it uses DefaultDataSize if dataSize is 0.
+func New[DataType constraints.Integer](
+	dataSize int,
+	input brainfuck.InputReader[DataType],
+	output brainfuck.OutputWriter[DataType],
+) *Runner[DataType] {
+	if dataSize == 0 {
+		dataSize = brainfuck.DefaultDataSize
+	}
+
+	return &Runner[DataType]{
+		dataSize: dataSize,
+		input:    input,
+		output:   output,
+		fuel:     defaultFuel,
+	}
+}
+
+// WithEOFPolicy sets what In (',') command does when Input reaches the end of data
+func (r *Runner[DataType]) WithEOFPolicy(policy brainfuck.EOFPolicy) *Runner[DataType] {
+	r.eofPolicy = policy
+	return r
+}
+
+// Run compiles and runs the program. It returns the tape like BfInterpreter.Run does.
+func (r *Runner[DataType]) Run(commands io.Reader) ([]DataType, error) {
+	code, err := io.ReadAll(commands)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commands: %w", err)
+	}
+
+	return r.run(code)
+}
+
+// interpret runs the program with BfInterpreter
+func (r *Runner[DataType]) interpret(code []byte) ([]DataType, error) {
+	return brainfuck.New[DataType](r.dataSize, r.input, r.output).
+		WithEOFPolicy(r.eofPolicy).
+		Run(bytes.NewReader(code))
+}
@@ -0,0 +1,146 @@
+//go:build linux && amd64
+
+package jit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+
+	"golang.org/x/sys/unix"
+)
+
+// Supported reports whether programs are compiled to native code on this platform
+const Supported = true
+
+// maxTapeBytes keeps the tape size in imm32 range
+const maxTapeBytes = 1<<31 - 1
+
+// callJIT runs compiled code from the code address. It's implemented in call_amd64.s.
+func callJIT(code, mem, ptr, fuel uintptr) (status, newPtr, resume, cmd uintptr)
+
+func (r *Runner[DataType]) run(code []byte) ([]DataType, error) {
+	var zero DataType
+	cellBytes := int(unsafe.Sizeof(zero))
+	tapeBytes := r.dataSize * cellBytes
+
+	prog, err := ir.Parse(bytes.NewReader(code))
+	if err != nil || r.dataSize <= 0 || int64(r.dataSize)*int64(cellBytes) > maxTapeBytes {
+		// BfInterpreter reports such programs in its own way
+		return r.interpret(code)
+	}
+
+	// ir.Optimize doesn't fold moves in opposite directions, so compiled code leaves the tape where the interpreter does
+	machineCode, err := compile(ir.Optimize(prog), cellBytes, tapeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile program: %w", err)
+	}
+
+	exec, err := unix.Mmap(-1, 0, len(machineCode), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map code: %w", err)
+	}
+	defer unix.Munmap(exec)
+
+	copy(exec, machineCode)
+
+	if err := unix.Mprotect(exec, unix.PROT_READ|unix.PROT_EXEC); err != nil {
+		return nil, fmt.Errorf("failed to protect code: %w", err)
+	}
+
+	mem, err := unix.Mmap(-1, 0, tapeBytes, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map tape: %w", err)
+	}
+	defer unix.Munmap(mem)
+
+	tape := unsafe.Slice((*DataType)(unsafe.Pointer(&mem[0])), r.dataSize)
+
+	codeAddr := uintptr(unsafe.Pointer(&exec[0]))
+	memAddr := uintptr(unsafe.Pointer(&mem[0]))
+
+	var ptr, resume uintptr
+
+	for {
+		var st, cmd uintptr
+		st, ptr, resume, cmd = callJIT(codeAddr+resume, memAddr, ptr, r.fuel)
+
+		cmdPtr := brainfuck.CmdPtrType(cmd)
+
+		switch status(st) {
+		case statusDone:
+			data := make([]DataType, r.dataSize)
+			copy(data, tape)
+
+			return data, nil
+
+		case statusOut:
+			if err := r.output.Write(tape[ptr/uintptr(cellBytes)]); err != nil {
+				return nil, &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '.', Err: fmt.Errorf("failed to write value: %w", err)}
+			}
+
+		case statusIn:
+			dataPtr := ptr / uintptr(cellBytes)
+			if err := r.read(&tape[dataPtr], cmdPtr, brainfuck.DataPtrType(dataPtr)); err != nil {
+				return nil, &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: ',', Err: err}
+			}
+
+		// resume is the number of cells of the move, ptr is moved already
+		case statusShiftRight:
+			from := int(ptr)/cellBytes - int(resume)
+			cmdPtr += brainfuck.CmdPtrType(r.dataSize - 1 - from)
+
+			return nil, &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '>', Err: fmt.Errorf("shift+ moves out of boundary")}
+
+		case statusShiftLeft:
+			from := int(ptr+resume*uintptr(cellBytes)) / cellBytes
+			cmdPtr += brainfuck.CmdPtrType(from)
+
+			return nil, &brainfuck.CmdError{CmdPtr: cmdPtr, Cmd: '<', Err: fmt.Errorf("shift- moves out of boundary")}
+
+		case statusYield:
+
+		default:
+			return nil, fmt.Errorf("unknown status of compiled code: %d", st)
+		}
+	}
+}
+
+// read implements In command like BfInterpreter does
+func (r *Runner[DataType]) read(cell *DataType, cmdPtr brainfuck.CmdPtrType, dataPtr brainfuck.DataPtrType) error {
+	v, err := r.input.Read(prompt.Hint[DataType]{
+		Text:    brainfuck.DefaultPrompt(cmdPtr, dataPtr, *cell),
+		CmdPtr:  int(cmdPtr),
+		DataPtr: int(dataPtr),
+		Value:   *cell,
+	})
+
+	if errors.Is(err, io.EOF) && r.eofPolicy != brainfuck.EOFError {
+		switch r.eofPolicy {
+		case brainfuck.EOFZero:
+			*cell = 0
+		case brainfuck.EOFMinusOne:
+			*cell = 0
+			*cell--
+		case brainfuck.EOFNoChange:
+		default:
+			return fmt.Errorf("unknown EOF policy: %d", r.eofPolicy)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read value: %w", err)
+	}
+
+	*cell = v
+
+	return nil
+}
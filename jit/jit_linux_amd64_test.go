@@ -0,0 +1,67 @@
+//go:build linux && amd64
+
+package jit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunner_Yield makes compiled code return to Go on every loop iteration
+func TestRunner_Yield(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Supported)
+
+	expOutput := writer.BuildSliceWriter[uint8]()
+	expData, err := brainfuck.New[uint8](64, reader.BuildStringReader[uint8]("Hello"), expOutput).
+		WithEOFPolicy(brainfuck.EOFNoChange).
+		Run(strings.NewReader(rot13))
+	require.NoError(t, err)
+
+	output := writer.BuildSliceWriter[uint8]()
+	r := New[uint8](64, reader.BuildStringReader[uint8]("Hello"), output).WithEOFPolicy(brainfuck.EOFNoChange)
+	r.fuel = 1
+
+	data, err := r.Run(strings.NewReader(rot13))
+	require.NoError(t, err)
+	require.Equal(t, "Uryyb", output.String())
+	require.Equal(t, expOutput.String(), output.String())
+	require.Equal(t, expData, data)
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	prog := mustParse(t, `+[-]>.`)
+
+	code, err := compile(prog, 1, 16)
+	require.NoError(t, err)
+
+	require.Equal(t, []byte{
+		0x80, 0x04, 0x37, 0x01, // add byte [rdi+rsi], 1
+		0xC6, 0x04, 0x37, 0x00, // mov byte [rdi+rsi], 0
+		0x48, 0x81, 0xC6, 0x01, 0x00, 0x00, 0x00, // add rsi, 1
+		0x48, 0x81, 0xFE, 0x10, 0x00, 0x00, 0x00, // cmp rsi, 16
+		0x0F, 0x83, 0x20, 0x00, 0x00, 0x00, // jae shift+ exit
+		0xB8, 0x01, 0x00, 0x00, 0x00, 0xB9, 0x05, 0x00, 0x00, 0x00, 0xBA, 0x2C, 0x00, 0x00, 0x00, 0xC3, // out
+		0xB8, 0x00, 0x00, 0x00, 0x00, 0xB9, 0x00, 0x00, 0x00, 0x00, 0xBA, 0x00, 0x00, 0x00, 0x00, 0xC3, // done
+		0xB8, 0x03, 0x00, 0x00, 0x00, 0xB9, 0x04, 0x00, 0x00, 0x00, 0xBA, 0x01, 0x00, 0x00, 0x00, 0xC3, // shift+ of 1 cell
+	}, code)
+}
+
+func mustParse(t *testing.T, code string) ir.Program {
+	t.Helper()
+
+	prog, err := ir.ParseString(code)
+	require.NoError(t, err)
+
+	return ir.Optimize(prog)
+}
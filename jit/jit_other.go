@@ -0,0 +1,10 @@
+//go:build !(linux && amd64)
+
+package jit
+
+// Supported reports whether programs are compiled to native code on this platform
+const Supported = false
+
+func (r *Runner[DataType]) run(code []byte) ([]DataType, error) {
+	return r.interpret(code)
+}
@@ -0,0 +1,225 @@
+package jit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/constraints"
+)
+
+const helloWorld = `++++++++[>++++[>++>+++>+++>+<<<<-]>+>+>->>+[<]<-]>>.>---.+++++++..+++.>>.<-.<.+++.------.--------.>>+.>++.`
+
+const rot13 = `-,+[-[>>++++[>++++++++<-]<+<-[>+>+>-[>>>]<[[>+<-]>>+>]<<<<<-]]>>>[-]+>--[-[<->+++[-]]]<[++++++++++++<[>-[>+>>]>[+[<+>-]>+>>]<<<<<-]>>[<+>-]>[-[-<<[-]>>]<<[<<->>-]>>]<<[<<+>>-]]<[-]<.[-]<-,+]`
+
+// failingWriter fails after limit values are written
+type failingWriter[DataType any] struct {
+	limit int
+}
+
+func (w *failingWriter[DataType]) Write(DataType) error {
+	if w.limit == 0 {
+		return errors.New("sink is full")
+	}
+
+	w.limit--
+
+	return nil
+}
+
+func (w *failingWriter[DataType]) Close() error {
+	return nil
+}
+
+// TestRunner_Run compares Runner with BfInterpreter
+func TestRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		code     string
+		input    string
+		policy   brainfuck.EOFPolicy
+		dataSize int
+	}
+
+	tests := map[string]Test{
+		"hello world": {
+			code: helloWorld,
+		},
+
+		"rot13": {
+			code:   rot13,
+			input:  "Hello, World!",
+			policy: brainfuck.EOFNoChange,
+		},
+
+		"echo": {
+			code:   `,[.,]`,
+			input:  "echo me",
+			policy: brainfuck.EOFZero,
+		},
+
+		"cell wraps": {
+			code: `-.+.[-]+++[>+++++<-]>.` + strings.Repeat("+", 300) + ".",
+		},
+
+		"long loops": {
+			code: `++++++++[>++++++++[>++++++++[>++++++++[>++++++++<-]<-]<-]<-]>>>>.`,
+		},
+
+		"EOF minus one": {
+			code:   `,.`,
+			policy: brainfuck.EOFMinusOne,
+		},
+
+		"EOF no change": {
+			code:   `++,.`,
+			policy: brainfuck.EOFNoChange,
+		},
+
+		"EOF error": {
+			code:  `,.,.`,
+			input: "a",
+		},
+
+		"out of boundary": {
+			code: `+.<`,
+		},
+
+		"out of boundary right": {
+			code:     `>>+>>.>`,
+			dataSize: 5,
+		},
+
+		"cancelled moves at left edge": {
+			code: `<>+.`,
+		},
+
+		"cancelled moves": {
+			code: `><+.`,
+		},
+
+		"moves back": {
+			code:     `>>><<+.`,
+			dataSize: 5,
+		},
+
+		"moves back from last cell": {
+			code:     `>>><<+.`,
+			dataSize: 4,
+		},
+
+		"folded moves out of boundary right": {
+			code:     `>>>`,
+			dataSize: 2,
+		},
+
+		"folded moves out of boundary left": {
+			code: `><<<`,
+		},
+
+		"separated moves out of boundary": {
+			code:     `>> >>>`,
+			dataSize: 4,
+		},
+
+		"unmatched loop": {
+			code: `+.]`,
+		},
+
+		"skipped unmatched loop": {
+			code: `[`,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		if test.dataSize == 0 {
+			test.dataSize = 16
+		}
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("uint8", func(t *testing.T) {
+				t.Parallel()
+				compare[uint8](t, test.code, test.input, test.dataSize, test.policy)
+			})
+
+			t.Run("int16", func(t *testing.T) {
+				t.Parallel()
+				compare[int16](t, test.code, test.input, test.dataSize, test.policy)
+			})
+
+			t.Run("uint32", func(t *testing.T) {
+				t.Parallel()
+				compare[uint32](t, test.code, test.input, test.dataSize, test.policy)
+			})
+
+			t.Run("int64", func(t *testing.T) {
+				t.Parallel()
+				compare[int64](t, test.code, test.input, test.dataSize, test.policy)
+			})
+		})
+	}
+}
+
+func compare[DataType constraints.Integer](t *testing.T, code, input string, dataSize int, policy brainfuck.EOFPolicy) {
+	t.Helper()
+
+	expOutput := writer.BuildSliceWriter[DataType]()
+	expData, expErr := brainfuck.New[DataType](dataSize, reader.BuildStringReader[DataType](input), expOutput).
+		WithEOFPolicy(policy).
+		Run(strings.NewReader(code))
+
+	output := writer.BuildSliceWriter[DataType]()
+	data, err := New[DataType](dataSize, reader.BuildStringReader[DataType](input), output).
+		WithEOFPolicy(policy).
+		Run(strings.NewReader(code))
+
+	require.Equal(t, expOutput.Values(), output.Values())
+	require.Equal(t, expData, data)
+
+	if expErr == nil {
+		require.NoError(t, err)
+		return
+	}
+
+	require.EqualError(t, err, expErr.Error())
+}
+
+func TestRunner_OutputError(t *testing.T) {
+	t.Parallel()
+
+	_, expErr := brainfuck.New[uint8](brainfuck.DefaultDataSize, nil, &failingWriter[uint8]{limit: 3}).
+		Run(strings.NewReader(helloWorld))
+
+	_, err := New[uint8](brainfuck.DefaultDataSize, nil, &failingWriter[uint8]{limit: 3}).
+		Run(strings.NewReader(helloWorld))
+
+	require.EqualError(t, err, expErr.Error())
+
+	var cmdErr *brainfuck.CmdError
+	require.True(t, errors.As(err, &cmdErr))
+	require.Equal(t, brainfuck.CmdType('.'), cmdErr.Cmd)
+}
+
+func TestNew_DefaultDataSize(t *testing.T) {
+	t.Parallel()
+
+	// like brainfuck.New, so programs are compiled rather than falling back to the interpreter
+	r := New[uint8](0, nil, writer.BuildSliceWriter[uint8]())
+	require.Equal(t, brainfuck.DefaultDataSize, r.dataSize)
+
+	data, err := r.Run(strings.NewReader(`+>++`))
+	require.NoError(t, err)
+	require.Len(t, data, brainfuck.DefaultDataSize)
+	require.Equal(t, []uint8{1, 2, 0}, data[:3])
+}
@@ -0,0 +1,265 @@
+package jit
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck/ir"
+)
+
+// Register usage of compiled code:
+//
+//	RDI – tape address
+//	RSI – data pointer (byte offset of the current cell)
+//	R8  – fuel, the number of loop iterations before returning to Go
+//
+// Compiled code returns with status in EAX, the offset of the command in ECX
+// and the offset of code to resume from in EDX. Moves out of the tape return the number of cells of the move
+// in EDX instead, so the command that leaves the tape may be found (see ir.Instr).
+
+// status is a reason why compiled code returned to Go
+type status uintptr
+
+const (
+	statusDone status = iota
+	statusOut
+	statusIn
+	statusShiftRight
+	statusShiftLeft
+	statusYield
+)
+
+// fixup is a rel32 operand that is resolved when the target is known
+type fixup struct {
+	// at is the offset of the operand
+	at int
+
+	// target is an instruction index (loops) or a stub index (stubs)
+	target int
+}
+
+// stub is an out-of-line exit
+type stub struct {
+	status status
+	cmd    int
+	resume int
+}
+
+// assembler compiles a program to x86-64 machine code
+type assembler struct {
+	code      []byte
+	cellBytes int
+	tapeBytes int
+
+	// loopEnds are code offsets after OpEnd instructions, loopBodies are code offsets after OpLoop instructions
+	loopEnds   map[int]int
+	loopBodies map[int]int
+
+	loopFixups []fixup
+	stubFixups []fixup
+	stubs      []stub
+}
+
+// compile translates an optimized program to machine code
+func compile(prog ir.Program, cellBytes, tapeBytes int) ([]byte, error) {
+	a := assembler{
+		cellBytes:  cellBytes,
+		tapeBytes:  tapeBytes,
+		loopEnds:   make(map[int]int),
+		loopBodies: make(map[int]int),
+	}
+
+	for i, in := range prog {
+		if err := a.instr(i, in); err != nil {
+			return nil, err
+		}
+	}
+
+	a.exit(statusDone, 0, 0)
+
+	// loops
+	for _, f := range a.loopFixups {
+		target, ok := a.loopEnds[f.target]
+		if !ok {
+			target, ok = a.loopBodies[f.target]
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("unresolved jump [#instr: %d]", f.target)
+		}
+
+		a.patch(f.at, target)
+	}
+
+	// out-of-line exits
+	stubOffsets := make([]int, len(a.stubs))
+	for i, s := range a.stubs {
+		stubOffsets[i] = len(a.code)
+		a.exit(s.status, s.cmd, s.resume)
+	}
+
+	for _, f := range a.stubFixups {
+		a.patch(f.at, stubOffsets[f.target])
+	}
+
+	return a.code, nil
+}
+
+func (a *assembler) emit(b ...byte) {
+	a.code = append(a.code, b...)
+}
+
+func (a *assembler) imm32(v int32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(v))
+	a.code = append(a.code, b[:]...)
+}
+
+// patch sets rel32 operand at the offset to jump to the target
+func (a *assembler) patch(at, target int) {
+	binary.LittleEndian.PutUint32(a.code[at:], uint32(int32(target-(at+4))))
+}
+
+// exit returns to Go: mov eax, status; mov ecx, cmd; mov edx, resume; ret
+func (a *assembler) exit(s status, cmd, resume int) {
+	a.emit(0xB8)
+	a.imm32(int32(s))
+	a.emit(0xB9)
+	a.imm32(int32(cmd))
+	a.emit(0xBA)
+	a.imm32(int32(resume))
+	a.emit(0xC3)
+}
+
+// jccStub emits a conditional jump (0F cc rel32) to an out-of-line exit
+func (a *assembler) jccStub(cc byte, s stub) {
+	a.emit(0x0F, cc)
+	a.stubFixups = append(a.stubFixups, fixup{at: len(a.code), target: len(a.stubs)})
+	a.imm32(0)
+	a.stubs = append(a.stubs, s)
+}
+
+// jccLoop emits a conditional jump (0F cc rel32) to the other end of a loop
+func (a *assembler) jccLoop(cc byte, instr int) {
+	a.emit(0x0F, cc)
+	a.loopFixups = append(a.loopFixups, fixup{at: len(a.code), target: instr})
+	a.imm32(0)
+}
+
+// cellOp emits an instruction with [rdi+rsi] operand for the cell width.
+// op8 is the opcode for byte cells, op is the opcode for wider cells, ext is ModRM reg field.
+func (a *assembler) cellOp(op8, op, ext byte) {
+	modrm := 0x04 | ext<<3
+
+	switch a.cellBytes {
+	case 1:
+		a.emit(op8, modrm, 0x37)
+	case 2:
+		a.emit(0x66, op, modrm, 0x37)
+	case 4:
+		a.emit(op, modrm, 0x37)
+	default:
+		a.emit(0x48, op, modrm, 0x37)
+	}
+}
+
+// cellImm emits an immediate of the cell width (imm32 for 64-bit cells, it's sign-extended)
+func (a *assembler) cellImm(v int64) {
+	switch a.cellBytes {
+	case 1:
+		a.emit(byte(v))
+	case 2:
+		a.emit(byte(v), byte(v>>8))
+	default:
+		a.imm32(int32(v))
+	}
+}
+
+// testCell compares the current cell with zero: cmp cell, 0
+func (a *assembler) testCell() {
+	switch a.cellBytes {
+	case 1:
+		a.emit(0x80, 0x3C, 0x37, 0x00)
+	case 2:
+		a.emit(0x66, 0x83, 0x3C, 0x37, 0x00)
+	case 4:
+		a.emit(0x83, 0x3C, 0x37, 0x00)
+	default:
+		a.emit(0x48, 0x83, 0x3C, 0x37, 0x00)
+	}
+}
+
+func (a *assembler) instr(i int, in ir.Instr) error {
+	switch in.Op {
+	case ir.OpAdd:
+		// add cell, imm; 64-bit cells take sign-extended imm32, so big deltas are split
+		n := int64(in.Arg)
+		for n != 0 {
+			step := n
+			if a.cellBytes == 8 && (step > 1<<31-1 || step < -1<<31) {
+				step = 1<<31 - 1
+				if n < 0 {
+					step = -1 << 31
+				}
+			}
+
+			a.cellOp(0x80, 0x81, 0)
+			a.cellImm(step)
+			n -= step
+		}
+
+	case ir.OpMove:
+		delta := int64(in.Arg) * int64(a.cellBytes)
+		if delta > 1<<31-1 || delta < -(1<<31-1) {
+			return fmt.Errorf("move is too long [#cmd: %d]", in.Offset)
+		}
+
+		if delta > 0 {
+			// add rsi, delta; cmp rsi, tapeBytes; jae shift+ error
+			a.emit(0x48, 0x81, 0xC6)
+			a.imm32(int32(delta))
+			a.emit(0x48, 0x81, 0xFE)
+			a.imm32(int32(a.tapeBytes))
+			a.jccStub(0x83, stub{status: statusShiftRight, cmd: in.Offset, resume: in.Arg})
+		} else {
+			// sub rsi, -delta; jb shift- error
+			a.emit(0x48, 0x81, 0xEE)
+			a.imm32(int32(-delta))
+			a.jccStub(0x82, stub{status: statusShiftLeft, cmd: in.Offset, resume: -in.Arg})
+		}
+
+	case ir.OpOut:
+		a.exit(statusOut, in.Offset, len(a.code)+exitSize)
+
+	case ir.OpIn:
+		a.exit(statusIn, in.Offset, len(a.code)+exitSize)
+
+	case ir.OpLoop:
+		// cmp cell, 0; jz after the loop end
+		a.testCell()
+		a.jccLoop(0x84, in.Arg)
+		a.loopBodies[i] = len(a.code)
+
+	case ir.OpEnd:
+		// dec r8; jz yield (resuming from the cell check); cmp cell, 0; jnz loop body
+		resume := len(a.code)
+		a.emit(0x49, 0xFF, 0xC8)
+		a.jccStub(0x84, stub{status: statusYield, cmd: in.Offset, resume: resume + 3 + 6})
+		a.testCell()
+		a.jccLoop(0x85, in.Arg)
+		a.loopEnds[i] = len(a.code)
+
+	case ir.OpClear:
+		// mov cell, 0
+		a.cellOp(0xC6, 0xC7, 0)
+		a.cellImm(0)
+
+	default:
+		return fmt.Errorf("unknown instruction: %s", in.Op)
+	}
+
+	return nil
+}
+
+// exitSize is the size of exit code
+const exitSize = 16
@@ -48,7 +48,7 @@ func (mr *MockTestInputReaderMockRecorder) Close() *gomock.Call {
 }
 
 // Read mocks base method.
-func (m *MockTestInputReader) Read(arg0 string) (TestDataType, error) {
+func (m *MockTestInputReader) Read(arg0 TestHint) (TestDataType, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Read", arg0)
 	ret0, _ := ret[0].(TestDataType)
@@ -0,0 +1,121 @@
+package brainfuck
+
+import (
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck/stack"
+)
+
+// Multi-tape and auxiliary stack commands
+const (
+	// CmdSwitchTape switches to the next tape
+	CmdSwitchTape = CmdType('^')
+
+	// CmdPushAux pushes the current cell to the auxiliary stack
+	CmdPushAux = CmdType('$')
+
+	// CmdPopAux pops a value from the auxiliary stack to the current cell
+	CmdPopAux = CmdType('~')
+)
+
+// EnableMultiTape enables Switch Tape ('^') command that cycles through n tapes.
+// Every tape has its own data pointer. Secondary tapes have the same size as the main one and are sparse
+// if the main one is set with WithTape.
+// Every Run starts with the main tape, secondary tapes are cleared. Run switches back to the main tape when it ends,
+// so it returns the main tape and a PointerTape saves its own pointer.
+func EnableMultiTape[DataType any](bf *BfInterpreter[DataType], n int) *BfInterpreter[DataType] {
+	if n < 2 {
+		return bf
+	}
+
+	type tapeState struct {
+		data    []DataType
+		tape    Tape[DataType]
+		dataPtr DataPtrType
+	}
+
+	tapes := make([]tapeState, n)
+	current := 0
+
+	// switchTo saves the memory and the pointer of the active tape and activates tape i
+	switchTo := func(bf *BfInterpreter[DataType], i int) {
+		tapes[current] = tapeState{
+			data:    bf.Data,
+			tape:    bf.Tape,
+			dataPtr: bf.DataPtr,
+		}
+
+		current = i
+		next := tapes[current]
+
+		if next.data == nil && next.tape == nil {
+			next = tapeState{tape: BuildSparseTape[DataType](bf.TapeLen())}
+
+			if bf.Tape == nil {
+				next = tapeState{data: make([]DataType, len(bf.Data))}
+
+				zero := bf.arith.Zero()
+				for i := range next.data {
+					next.data[i] = zero
+				}
+			}
+		}
+
+		bf.Data = next.data
+		bf.Tape = next.tape
+		bf.DataPtr = next.dataPtr
+	}
+
+	bf.OnReset(func() {
+		if current != 0 {
+			bf.Data = tapes[0].data
+			bf.Tape = tapes[0].tape
+			current = 0
+		}
+
+		// secondary tapes are allocated on the first switch
+		for i := 1; i < n; i++ {
+			tapes[i] = tapeState{}
+		}
+	})
+
+	bf.OnFinish(func() {
+		if current != 0 {
+			switchTo(bf, 0)
+		}
+	})
+
+	return bf.WithCmd(CmdSwitchTape, func(bf *BfInterpreter[DataType]) error {
+		switchTo(bf, (current+1)%n)
+		return nil
+	})
+}
+
+// EnableAuxStack enables commands of an auxiliary stack: Push ('$') copies the current cell to the stack,
+// Pop ('~') moves the top value of the stack to the current cell. Pop fails if the stack is empty.
+// The stack is cleared at the start of every Run.
+func EnableAuxStack[DataType any](bf *BfInterpreter[DataType]) *BfInterpreter[DataType] {
+	aux := stack.BuildStack[DataType]()
+
+	bf.OnReset(func() {
+		aux = stack.BuildStack[DataType]()
+	})
+
+	bf.WithCmd(CmdPushAux, func(bf *BfInterpreter[DataType]) error {
+		aux.Push(bf.Cell())
+		return nil
+	})
+
+	bf.WithCmd(CmdPopAux, func(bf *BfInterpreter[DataType]) error {
+		v := aux.Pop()
+		if v == nil {
+			return fmt.Errorf("auxiliary stack is empty")
+		}
+
+		bf.SetCell(*v)
+
+		return nil
+	})
+
+	return bf
+}
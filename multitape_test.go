@@ -0,0 +1,80 @@
+package brainfuck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableMultiTape(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		srcCommands string
+		sparse      bool
+
+		expOutput []TestDataType
+		expData   []TestDataType
+	}
+
+	tests := map[string]Test{
+		"tapes have own pointers": {
+			srcCommands: `+++>^++>>-^^.<.^+`,
+			expOutput:   []TestDataType{0, 3},
+			expData:     []TestDataType{3, 0, 0}, // the main tape is returned though the second one is active at the end
+		},
+
+		"sparse tapes": {
+			srcCommands: `+^++.^^.`,
+			sparse:      true,
+			expOutput:   []TestDataType{2, 1},
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[TestDataType]()
+
+			bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), output)
+			if test.sparse {
+				bf.WithTape(BuildSparseTape[TestDataType](1 << 20))
+			}
+
+			EnableMultiTape(bf, 3)
+
+			resData, err := bf.Run(strings.NewReader(test.srcCommands))
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, output.Values())
+
+			if test.expData != nil {
+				require.Equal(t, test.expData, resData)
+			}
+		})
+	}
+}
+
+func TestEnableAuxStack(t *testing.T) {
+	t.Parallel()
+
+	output := writer.BuildSliceWriter[TestDataType]()
+
+	bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), output)
+	EnableAuxStack(bf)
+
+	// values are popped in reverse order
+	_, err := bf.Run(strings.NewReader(`+$+$>~.~.`))
+	require.NoError(t, err)
+	require.Equal(t, []TestDataType{2, 1}, output.Values())
+
+	_, err = bf.Run(strings.NewReader(`$~~`))
+	require.Error(t, err)
+}
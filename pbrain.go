@@ -0,0 +1,83 @@
+package brainfuck
+
+import (
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck/stack"
+
+	"golang.org/x/exp/constraints"
+)
+
+// pbrain commands
+const (
+	// CmdProcStart starts a definition of a procedure that is numbered by the current cell
+	CmdProcStart = CmdType('(')
+
+	// CmdProcEnd ends a procedure definition and returns from a procedure when it's called
+	CmdProcEnd = CmdType(')')
+
+	// CmdProcCall calls a procedure that is numbered by the current cell
+	CmdProcCall = CmdType(':')
+)
+
+// EnablePbrain enables pbrain extension: procedures that are defined with '(' and ')' and called with ':'.
+// A procedure body is skipped when it's defined and is run only by calls. Procedures may call each other and themselves.
+// All commands are retained in cache, as procedures may be called from any place of the program.
+// Every call has its own loop stack, so a procedure may recurse through a loop.
+func EnablePbrain[DataType constraints.Integer](bf *BfInterpreter[DataType]) *BfInterpreter[DataType] {
+
+	// procs stores procedures starts by their numbers
+	procs := make(map[DataType]CmdPtrType)
+
+	// calls stores return addresses and loop stacks of callers
+	calls := stack.BuildStack[procCall]()
+
+	bf.RetainCommands()
+
+	bf.OnReset(func() {
+		procs = make(map[DataType]CmdPtrType)
+		calls = stack.BuildStack[procCall]()
+	})
+
+	bf.WithCmd(CmdProcStart, func(bf *BfInterpreter[DataType]) error {
+		procs[bf.Cell()] = bf.CmdPtr
+		return bf.JumpToMatching(CmdProcStart, CmdProcEnd)
+	})
+
+	bf.WithCmd(CmdProcEnd, func(bf *BfInterpreter[DataType]) error {
+		call := calls.Pop()
+		if call == nil {
+			return fmt.Errorf("procedure end without a call")
+		}
+
+		bf.loopStack = call.loopStack
+		bf.CmdPtr = call.ret // bf.CmdPtr will be incremented
+
+		return nil
+	})
+
+	bf.WithCmd(CmdProcCall, func(bf *BfInterpreter[DataType]) error {
+		start, ok := procs[bf.Cell()]
+		if !ok {
+			return fmt.Errorf("procedure %d is not defined", bf.Cell())
+		}
+
+		// the same '[' may be entered again by a recursive call, so the procedure starts with a clean loop stack
+		calls.Push(procCall{ret: bf.CmdPtr, loopStack: bf.loopStack})
+		bf.loopStack = stack.BuildStack[CmdPtrType]()
+		bf.CmdPtr = start // bf.CmdPtr will be incremented
+
+		return nil
+	})
+
+	return bf
+}
+
+// procCall is a frame of a procedure call
+type procCall struct {
+	// ret is the address of the call command
+	ret CmdPtrType
+
+	// loopStack is the loop stack of the caller
+	loopStack *stack.Stack[CmdPtrType]
+}
@@ -0,0 +1,88 @@
+package brainfuck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnablePbrain(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		srcCommands string
+
+		expErr    bool
+		expOutput []TestDataType
+	}
+
+	tests := map[string]Test{
+		"define and call": {
+			srcCommands: `+(>++.<)::`,
+			expOutput:   []TestDataType{2, 4},
+		},
+
+		"call from loop": {
+			srcCommands: `+(>+.<)>>+++[<<:>>-]`,
+			expOutput:   []TestDataType{1, 2, 3},
+		},
+
+		"procedure is chosen by the cell": {
+			srcCommands: `+(>+.<)+(-:+:)+(.):`,
+			expOutput:   []TestDataType{3},
+		},
+
+		"procedure calls another one": {
+			srcCommands: `+(>+.<)+(-:+)::`,
+			expOutput:   []TestDataType{1, 2},
+		},
+
+		"recursion through a loop": {
+			srcCommands: `(>[-.<:>]<)>+++<:`,
+			expOutput:   []TestDataType{2, 1, 0},
+		},
+
+		"recursion ends in a loop of the caller": {
+			srcCommands: `(>[-<:>]<)>++<+[-:]>.`,
+			expOutput:   []TestDataType{0},
+		},
+
+		"undefined procedure": {
+			srcCommands: `+:`,
+			expErr:      true,
+		},
+
+		"procedure end without call": {
+			srcCommands: `)`,
+			expErr:      true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			output := writer.BuildSliceWriter[TestDataType]()
+
+			bf := New[TestDataType](4, reader.BuildSliceReader[TestDataType](), output)
+			EnablePbrain(bf)
+
+			_, err := bf.Run(strings.NewReader(test.srcCommands))
+
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, output.Values())
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package brainfuck
+
+import "sync"
+
+// Pool keeps interpreters for reuse, so services that execute many short programs don't allocate memory for each run.
+// Interpreters are created by the build function, so they keep commands and settings that it registers.
+// It's safe for concurrent use.
+type Pool[DataType any] struct {
+	p sync.Pool
+}
+
+// NewPool creates Pool instance. build is called when there's no free interpreter in the pool.
+func NewPool[DataType any](build func() *BfInterpreter[DataType]) *Pool[DataType] {
+	return &Pool[DataType]{
+		p: sync.Pool{
+			New: func() any {
+				return build()
+			},
+		},
+	}
+}
+
+// Get returns a clean interpreter. Input and Output are those that were set before it was put to the pool,
+// so they usually should be replaced before Run.
+func (p *Pool[DataType]) Get() *BfInterpreter[DataType] {
+	return p.p.Get().(*BfInterpreter[DataType])
+}
+
+// Put resets the interpreter and returns it to the pool. It must not be used after that.
+func (p *Pool[DataType]) Put(bf *BfInterpreter[DataType]) {
+	bf.Reset()
+	p.p.Put(bf)
+}
@@ -0,0 +1,542 @@
+// Package preprocess turns annotated brainfuck sources into pure brainfuck code with a map back to the sources.
+//
+// Sources may contain:
+//
+//	// comment        – the rest of the line is ignored, commands in comments are never emitted (unless '/' is a command)
+//	#include "file.b" – inserts a file, the path is relative to the including file
+//	#define name body – defines a macro, the body is the rest of the line
+//	#define name(a, b) body with @a and @b
+//	@name             – expands a macro
+//	@name(>>, +++)    – expands a macro with arguments
+//	+*10              – repeats a command or a macro expansion (@name*3)
+//
+// Repetition counts are limited by 2^20 and the result is limited by 2^24 commands.
+// Only lines that start with #include or #define are directives, other lines starting with '#' are code.
+// Any text that isn't a command is dropped, so the result contains commands only. Commands are brainfuck ones by
+// default, WithCommands sets the commands of an interpreter with extensions, i.e. pbrain or the '#' dump.
+// Every command of the result is mapped to the file, line and column where it's written (see sourcemap package).
+package preprocess
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yurii-vyrovyi/brainfuck/sourcemap"
+)
+
+// maxExpansionDepth limits nested macro expansions, so recursive macros fail instead of hanging
+const maxExpansionDepth = 64
+
+// maxRepeat limits a repetition count (+*N)
+const maxRepeat = 1 << 20
+
+// defaultMaxCodeSize limits the number of commands in the result, so nested repetitions don't exhaust memory
+const defaultMaxCodeSize = 1 << 24
+
+// defaultCommands are brainfuck commands that are emitted to the result unless WithCommands is used
+var defaultCommands = []byte("+-<>[].,")
+
+// directives are keywords that make a line starting with '#' a directive
+var directives = []string{"include", "define"}
+
+// Position is a location in a source file. Line and Column start with 1.
+type Position = sourcemap.Position
+
+// Result is preprocessed code with its source map
+type Result struct {
+
+	// Code is pure brainfuck code
+	Code []byte
+
+	// Map stores the source position of every command of Code
+	Map *sourcemap.Map
+}
+
+// Position returns the source position of a command by its offset in Code, i.e. by CmdPtr reported by Run
+func (r *Result) Position(offset int) (Position, bool) {
+	return r.Map.Lookup(offset)
+}
+
+// Preprocessor processes sources from a file system. Macros are shared between all processed files.
+// Included files should be in the file system, i.e. "../lib.b" can't be included by a file in its root.
+type Preprocessor struct {
+	fsys   fs.FS
+	macros map[string]*macro
+
+	// commands are characters that are emitted to the result
+	commands map[byte]bool
+
+	// maxCodeSize is the maximum number of commands in the result
+	maxCodeSize int
+
+	// including is a stack of files that are being processed, it's used to detect include cycles
+	including []string
+
+	res Result
+}
+
+// macro is a macro definition
+type macro struct {
+	params []string
+	body   []char
+	pos    Position
+}
+
+// char is a source character with its position
+type char struct {
+	c   byte
+	pos Position
+}
+
+// New creates Preprocessor instance that reads files from fsys.
+func New(fsys fs.FS) *Preprocessor {
+	return WithCommands(&Preprocessor{
+		fsys:        fsys,
+		macros:      make(map[string]*macro),
+		maxCodeSize: defaultMaxCodeSize,
+	}, defaultCommands)
+}
+
+// WithCommands sets commands that are emitted to the result, i.e. the ones of an interpreter with extensions:
+//
+//	pp := preprocess.WithCommands(preprocess.New(fsys), bf.Commands())
+//
+// It's a function rather than a method to accept brainfuck.CmdType without importing the interpreter package.
+// '@' and '*' are reserved for macros and repetitions and can't be commands.
+func WithCommands[CmdType ~byte](p *Preprocessor, cmds []CmdType) *Preprocessor {
+	p.commands = make(map[byte]bool, len(cmds))
+	for _, cmd := range cmds {
+		p.commands[byte(cmd)] = true
+	}
+
+	return p
+}
+
+// ProcessFile processes a file from the OS file system. Includes are resolved relative to its directory.
+// Files are read from the working directory if the file is in it, so includes may refer to parent directories
+// ("../lib.b") up to the working directory. Otherwise, files are read from the directory of the file.
+func ProcessFile(fileName string) (*Result, error) {
+	if root, name, ok := workingDirPath(fileName); ok {
+		return New(os.DirFS(root)).Process(name)
+	}
+
+	dir, name := filepath.Split(fileName)
+	if dir == "" {
+		dir = "."
+	}
+
+	return New(os.DirFS(dir)).Process(name)
+}
+
+// workingDirPath returns the working directory and the path of the file relative to it if the file is in it
+func workingDirPath(fileName string) (string, string, bool) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", false
+	}
+
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", "", false
+	}
+
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", false
+	}
+
+	return wd, filepath.ToSlash(rel), true
+}
+
+// Process processes a file and all files that it includes.
+func (p *Preprocessor) Process(name string) (*Result, error) {
+	p.res = Result{Map: sourcemap.New()}
+
+	if err := p.processFile(name); err != nil {
+		return nil, err
+	}
+
+	res := p.res
+	p.res = Result{}
+
+	return &res, nil
+}
+
+func (p *Preprocessor) processFile(name string) error {
+	for _, f := range p.including {
+		if f == name {
+			return fmt.Errorf("include cycle: %s -> %s", strings.Join(p.including, " -> "), name)
+		}
+	}
+
+	src, err := fs.ReadFile(p.fsys, name)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	p.including = append(p.including, name)
+	defer func() { p.including = p.including[:len(p.including)-1] }()
+
+	// text collects lines between directives
+	var text []char
+
+	for i, line := range strings.Split(string(src), "\n") {
+		chars := make([]char, 0, len(line))
+		for j := 0; j < len(line); j++ {
+			chars = append(chars, char{c: line[j], pos: Position{File: name, Line: i + 1, Column: j + 1}})
+		}
+
+		// directives are detected before comments are stripped, and there are no comments if '/' is a command
+		directive := isDirective(chars)
+		if !p.commands['/'] {
+			chars = stripComment(chars)
+		}
+
+		if !directive {
+			text = append(text, chars...)
+			text = append(text, char{c: '\n'})
+			continue
+		}
+
+		if err := p.expand(text, 0); err != nil {
+			return err
+		}
+		text = nil
+
+		if err := p.processDirective(name, chars); err != nil {
+			return err
+		}
+	}
+
+	return p.expand(text, 0)
+}
+
+func (p *Preprocessor) processDirective(fileName string, chars []char) error {
+	chars = trimSpace(chars)
+	pos := chars[0].pos
+
+	word, rest := splitWord(chars[1:])
+
+	// isDirective allows known directives only
+	switch word {
+	case "include":
+		inc := strings.TrimSpace(toString(rest))
+		if len(inc) < 2 || inc[0] != '"' || inc[len(inc)-1] != '"' {
+			return fmt.Errorf("%s: include expects a quoted file name", pos)
+		}
+
+		name := path.Join(path.Dir(fileName), inc[1:len(inc)-1])
+
+		// fs.FS doesn't open paths with ".." and absolute paths
+		if !fs.ValidPath(name) {
+			return fmt.Errorf("%s: include %s is outside of the root directory", pos, inc)
+		}
+
+		return p.processFile(name)
+
+	case "define":
+		return p.define(pos, trimSpace(rest))
+
+	default:
+		return fmt.Errorf("%s: unknown directive #%s", pos, word)
+	}
+}
+
+// isDirective reports whether a line is a directive, i.e. "#include" or "#define" followed by a space.
+// Other lines that start with '#' are code, as '#' may be a command.
+func isDirective(chars []char) bool {
+	chars = trimSpace(chars)
+	if len(chars) == 0 || chars[0].c != '#' {
+		return false
+	}
+
+	word, rest := splitWord(chars[1:])
+	if len(rest) > 0 && !isSpace(rest[0].c) {
+		return false
+	}
+
+	return indexOf(directives, word) >= 0
+}
+
+// define parses a macro definition: "name body" or "name(a, b) body"
+func (p *Preprocessor) define(pos Position, chars []char) error {
+	i := 0
+	for i < len(chars) && isIdentChar(chars[i].c) {
+		i++
+	}
+
+	name := toString(chars[:i])
+	if name == "" {
+		return fmt.Errorf("%s: macro name expected", pos)
+	}
+
+	m := macro{pos: pos}
+
+	if i < len(chars) && chars[i].c == '(' {
+		args, end, err := parseArgs(chars, i)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range args {
+			param := strings.TrimSpace(toString(arg))
+			if !isIdent(param) {
+				return fmt.Errorf("%s: invalid parameter %q of macro %s", pos, param, name)
+			}
+
+			m.params = append(m.params, param)
+		}
+
+		i = end
+	}
+
+	m.body = chars[i:]
+	p.macros[name] = &m
+
+	return nil
+}
+
+// expand emits commands of the text and expands macros
+func (p *Preprocessor) expand(text []char, depth int) error {
+	if depth > maxExpansionDepth {
+		return fmt.Errorf("%s: macro expansion is too deep", text[0].pos)
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case p.commands[c.c] && c.c != '@' && c.c != '*':
+			n, end, err := parseRepeat(text, i+1)
+			if err != nil {
+				return err
+			}
+
+			if len(p.res.Code)+n > p.maxCodeSize {
+				return fmt.Errorf("%s: result is too large, it's limited by %d commands", c.pos, p.maxCodeSize)
+			}
+
+			for k := 0; k < n; k++ {
+				p.res.Code = append(p.res.Code, c.c)
+				p.res.Map.Append(c.pos)
+			}
+
+			i = end - 1
+
+		case c.c == '@':
+			expansion, end, err := p.macroCall(text, i)
+			if err != nil {
+				return err
+			}
+
+			n, end, err := parseRepeat(text, end)
+			if err != nil {
+				return err
+			}
+
+			for k := 0; k < n; k++ {
+				if err := p.expand(expansion, depth+1); err != nil {
+					return err
+				}
+			}
+
+			i = end - 1
+		}
+	}
+
+	return nil
+}
+
+// macroCall parses a macro call that starts at text[start] and returns the macro body with substituted arguments.
+func (p *Preprocessor) macroCall(text []char, start int) ([]char, int, error) {
+	pos := text[start].pos
+
+	end := start + 1
+	for end < len(text) && isIdentChar(text[end].c) {
+		end++
+	}
+
+	name := toString(text[start+1 : end])
+
+	m, ok := p.macros[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("%s: undefined macro %q", pos, name)
+	}
+
+	var args [][]char
+
+	if len(m.params) > 0 {
+		if end >= len(text) || text[end].c != '(' {
+			return nil, 0, fmt.Errorf("%s: macro %s expects %d arguments", pos, name, len(m.params))
+		}
+
+		var err error
+		args, end, err = parseArgs(text, end)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if len(args) != len(m.params) {
+			return nil, 0, fmt.Errorf("%s: macro %s expects %d arguments, got %d", pos, name, len(m.params), len(args))
+		}
+	}
+
+	// substituting parameters references (@param) with arguments
+	var res []char
+
+	for i := 0; i < len(m.body); i++ {
+		if m.body[i].c != '@' {
+			res = append(res, m.body[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(m.body) && isIdentChar(m.body[j].c) {
+			j++
+		}
+
+		idx := indexOf(m.params, toString(m.body[i+1:j]))
+		if idx < 0 {
+			res = append(res, m.body[i])
+			continue
+		}
+
+		res = append(res, args[idx]...)
+		i = j - 1
+	}
+
+	return res, end, nil
+}
+
+// parseArgs parses comma separated arguments in parentheses that start at text[start].
+// It returns arguments and the position after the closing parenthesis.
+func parseArgs(text []char, start int) ([][]char, int, error) {
+	var args [][]char
+	var arg []char
+
+	depth := 0
+
+	for i := start + 1; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case c.c == '(':
+			depth++
+		case c.c == ')' && depth == 0:
+			return append(args, arg), i + 1, nil
+		case c.c == ')':
+			depth--
+		case c.c == ',' && depth == 0:
+			args = append(args, arg)
+			arg = nil
+			continue
+		}
+
+		arg = append(arg, c)
+	}
+
+	return nil, 0, fmt.Errorf("%s: unclosed parenthesis", text[start].pos)
+}
+
+// parseRepeat parses an optional repetition suffix (*N) at text[start].
+// It returns the number of repetitions and the position after the suffix.
+func parseRepeat(text []char, start int) (int, int, error) {
+	if start >= len(text) || text[start].c != '*' {
+		return 1, start, nil
+	}
+
+	end := start + 1
+	for end < len(text) && text[end].c >= '0' && text[end].c <= '9' {
+		end++
+	}
+
+	n, err := strconv.Atoi(toString(text[start+1 : end]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: repetition count expected", text[start].pos)
+	}
+
+	if n > maxRepeat {
+		return 0, 0, fmt.Errorf("%s: repetition count %d exceeds %d", text[start].pos, n, maxRepeat)
+	}
+
+	return n, end, nil
+}
+
+// stripComment removes a line comment
+func stripComment(chars []char) []char {
+	for i := 0; i+1 < len(chars); i++ {
+		if chars[i].c == '/' && chars[i+1].c == '/' {
+			return chars[:i]
+		}
+	}
+
+	return chars
+}
+
+// splitWord returns the first word and the rest of the text
+func splitWord(chars []char) (string, []char) {
+	i := 0
+	for i < len(chars) && isIdentChar(chars[i].c) {
+		i++
+	}
+
+	return toString(chars[:i]), chars[i:]
+}
+
+func trimSpace(chars []char) []char {
+	for len(chars) > 0 && isSpace(chars[0].c) {
+		chars = chars[1:]
+	}
+
+	for len(chars) > 0 && isSpace(chars[len(chars)-1].c) {
+		chars = chars[:len(chars)-1]
+	}
+
+	return chars
+}
+
+func toString(chars []char) string {
+	b := make([]byte, len(chars))
+	for i, c := range chars {
+		b[i] = c.c
+	}
+
+	return string(b)
+}
+
+func indexOf(values []string, v string) int {
+	for i := range values {
+		if values[i] == v {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isIdentChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
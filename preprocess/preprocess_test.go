@@ -0,0 +1,271 @@
+package preprocess
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreprocessor_Process(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		files    fstest.MapFS
+		commands []byte
+
+		// maxCodeSize overrides the limit of the result size if it's set
+		maxCodeSize int
+
+		expErr  bool
+		expCode string
+	}
+
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	// commands of an interpreter with pbrain, dump, Brainfork, multiple tapes and aux stack extensions
+	extCommands := []byte("+-<>[].,():#Y^$~")
+
+	tests := map[string]Test{
+		"Plain code": {
+			files:   fstest.MapFS{"main.b": file("+ add. one\n[-]>")},
+			expCode: "+.[-]>",
+		},
+
+		"Comments": {
+			files:   fstest.MapFS{"main.b": file("+ // commands here, are ignored. [-]\n-")},
+			expCode: "+-",
+		},
+
+		"Repetition": {
+			files:   fstest.MapFS{"main.b": file("+*10>-*3<*0.")},
+			expCode: "++++++++++>---.",
+		},
+
+		"Macro": {
+			files:   fstest.MapFS{"main.b": file("#define clear [-]\n+@clear>@clear*2")},
+			expCode: "+[-]>[-][-]",
+		},
+
+		"Macro with parameters": {
+			files: fstest.MapFS{"main.b": file(
+				"#define move(from, to) @from[-@to+@from]\n" +
+					"@move(>, <)\n" +
+					"@move(>*4, <*4)",
+			)},
+			expCode: ">[-<+>]>>>>[-<<<<+>>>>]",
+		},
+
+		"Nested macros": {
+			files: fstest.MapFS{"main.b": file(
+				"#define inc(n) +*@n\n" +
+					"#define twice(x) @x@x\n" +
+					"@twice(@inc(3))",
+			)},
+			expCode: "++++++",
+		},
+
+		"Include": {
+			files: fstest.MapFS{
+				"main.b":      file("#include \"lib/clear.b\"\n+@clear"),
+				"lib/clear.b": file("#include \"zero.b\"\n#define clear @zero\n"),
+				"lib/zero.b":  file("#define zero [-]\n-"),
+			},
+			expCode: "-+[-]",
+		},
+
+		"Include cycle": {
+			files: fstest.MapFS{
+				"main.b": file("#include \"a.b\""),
+				"a.b":    file("#include \"main.b\""),
+			},
+			expErr: true,
+		},
+
+		"Missing include": {
+			files:  fstest.MapFS{"main.b": file("#include \"missing.b\"")},
+			expErr: true,
+		},
+
+		"Not a directive": {
+			files:   fstest.MapFS{"main.b": file("#pragma once\n#defined+\n  #[-]")},
+			expCode: "+[-]",
+		},
+
+		"Extension commands": {
+			files: fstest.MapFS{"main.b": file(
+				"#define call(n) +*@n:[-]\n" +
+					"(>+.<)   // pbrain procedure\n" +
+					"#        // dump\n" +
+					"Y^$~ @call(2)",
+			)},
+			commands: extCommands,
+			expCode:  "(>+.<)#Y^$~++:[-]",
+		},
+
+		"Slash command": {
+			files: fstest.MapFS{"main.b": file(
+				"#define slash //\n" +
+					"+ @slash // not a comment",
+			)},
+			commands: []byte("+/"),
+			expCode:  "+////",
+		},
+
+		"Comment is not a directive": {
+			files:   fstest.MapFS{"main.b": file("// #include \"missing.b\"\n+")},
+			expCode: "+",
+		},
+
+		"Directive with extension commands": {
+			files:    fstest.MapFS{"main.b": file("#include \"missing.b\"")},
+			commands: extCommands,
+			expErr:   true,
+		},
+
+		"Too many repetitions": {
+			files:  fstest.MapFS{"main.b": file("+*1000000000")},
+			expErr: true,
+		},
+
+		"Too large result": {
+			files: fstest.MapFS{"main.b": file(
+				"#define k +*1024\n" +
+					"#define m @k*1024\n" +
+					"@m*1024",
+			)},
+			maxCodeSize: 1 << 16,
+			expErr:      true,
+		},
+
+		"Undefined macro": {
+			files:  fstest.MapFS{"main.b": file("@foo")},
+			expErr: true,
+		},
+
+		"Wrong number of arguments": {
+			files:  fstest.MapFS{"main.b": file("#define m(a, b) @a@b\n@m(+)")},
+			expErr: true,
+		},
+
+		"Recursive macro": {
+			files:  fstest.MapFS{"main.b": file("#define m +@m\n@m")},
+			expErr: true,
+		},
+
+		"Unclosed parenthesis": {
+			files:  fstest.MapFS{"main.b": file("#define m(a) @a\n@m(+")},
+			expErr: true,
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			pp := New(test.files)
+			if test.commands != nil {
+				WithCommands(pp, test.commands)
+			}
+
+			if test.maxCodeSize != 0 {
+				pp.maxCodeSize = test.maxCodeSize
+			}
+
+			res, err := pp.Process("main.b")
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expCode, string(res.Code))
+			require.Equal(t, len(res.Code), res.Map.Length)
+		})
+	}
+}
+
+func TestResult_Position(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"main.b": &fstest.MapFile{Data: []byte("#include \"lib.b\"\n  +@dec(-)")},
+		"lib.b":  &fstest.MapFile{Data: []byte("// library\n#define dec(x) >@x<")},
+	}
+
+	res, err := New(files).Process("main.b")
+	require.NoError(t, err)
+	require.Equal(t, "+>-<", string(res.Code))
+
+	expPositions := []Position{
+		{File: "main.b", Line: 2, Column: 3},
+		{File: "lib.b", Line: 2, Column: 16},
+		{File: "main.b", Line: 2, Column: 9},
+		{File: "lib.b", Line: 2, Column: 19},
+	}
+
+	for i, exp := range expPositions {
+		pos, ok := res.Position(i)
+		require.True(t, ok)
+		require.Equal(t, exp, pos)
+	}
+
+	_, ok := res.Position(len(res.Code))
+	require.False(t, ok)
+
+	require.Equal(t, "lib.b:2:16", expPositions[1].String())
+}
+
+func TestPreprocessor_Process_ParentInclude(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"src/main.b": &fstest.MapFile{Data: []byte("#include \"../lib/inc.b\"\n@inc")},
+		"lib/inc.b":  &fstest.MapFile{Data: []byte("#define inc +")},
+	}
+
+	res, err := New(files).Process("src/main.b")
+	require.NoError(t, err)
+	require.Equal(t, "+", string(res.Code))
+
+	// the parent of the root directory isn't available
+	_, err = New(files).Process("lib/../src/main.b")
+	require.Error(t, err)
+
+	files["top.b"] = &fstest.MapFile{Data: []byte("#include \"../lib/inc.b\"")}
+
+	_, err = New(files).Process("top.b")
+	require.EqualError(t, err, `top.b:1:1: include "../lib/inc.b" is outside of the root directory`)
+}
+
+func TestProcessFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.b"), []byte("#include \"inc.b\"\n+"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "inc.b"), []byte("-"), 0644))
+
+	// the file is out of the working directory, so it's read from its own directory
+	res, err := ProcessFile(filepath.Join(dir, "src", "main.b"))
+	require.NoError(t, err)
+	require.Equal(t, "-+", string(res.Code))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	root, name, ok := workingDirPath(filepath.Join("testdata", "main.b"))
+	require.True(t, ok)
+	require.Equal(t, wd, root)
+	require.Equal(t, "testdata/main.b", name)
+
+	_, _, ok = workingDirPath(filepath.Join(dir, "src", "main.b"))
+	require.False(t, ok)
+}
@@ -0,0 +1,28 @@
+// Package prompt defines hints that the interpreter passes to input readers on In (',') command.
+package prompt
+
+import "github.com/yurii-vyrovyi/brainfuck/sourcemap"
+
+// Hint describes the context of In (',') command, so a reader may render a prompt appropriately.
+type Hint[DataType any] struct {
+
+	// Text is a formatted prompt. It's empty when prompts are disabled.
+	Text string
+
+	// CmdPtr is a position of the In command
+	CmdPtr int
+
+	// Position is the source position of the In command. It's nil if there's no source map or it has no position.
+	Position *sourcemap.Position
+
+	// DataPtr is an index of the cell that will get the value
+	DataPtr int
+
+	// Value is the current value of the cell
+	Value DataType
+}
+
+// String returns the formatted prompt
+func (h Hint[DataType]) String() string {
+	return h.Text
+}
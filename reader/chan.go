@@ -0,0 +1,47 @@
+package reader
+
+import (
+	"context"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+)
+
+// ChanReader implements brainfuck.InputReader.
+// It reads values from a channel, so an interpreter may consume output of another one running in a separate goroutine.
+// Read blocks until a value is received or the context is done.
+// A closed channel is reported as io.EOF, so the consuming interpreter handles it according to its EOF policy.
+type ChanReader[DataType any] struct {
+	ctx context.Context
+	ch  <-chan DataType
+}
+
+// BuildChanReader creates ChanReader instance that reads from ch until ctx is done.
+func BuildChanReader[DataType any](ctx context.Context, ch <-chan DataType) *ChanReader[DataType] {
+	return &ChanReader[DataType]{
+		ctx: ctx,
+		ch:  ch,
+	}
+}
+
+// Read waits for a value from the channel
+func (r *ChanReader[DataType]) Read(_ prompt.Hint[DataType]) (DataType, error) {
+	var zero DataType
+
+	select {
+	case <-r.ctx.Done():
+		return zero, r.ctx.Err()
+
+	case v, ok := <-r.ch:
+		if !ok {
+			return zero, io.EOF
+		}
+
+		return v, nil
+	}
+}
+
+// Close does nothing. The channel is owned and closed by the writing side.
+func (r *ChanReader[DataType]) Close() error {
+	return nil
+}
@@ -2,18 +2,21 @@ package reader
 
 import (
 	"bufio"
-	"golang.org/x/exp/constraints"
 	"os"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+
+	"golang.org/x/exp/constraints"
 )
 
 // FileReader implements brainfuck.InputReader.
-type FileReader[DataType constraints.Signed] struct {
+type FileReader[DataType constraints.Integer] struct {
 	f  *os.File
 	in *bufio.Reader
 }
 
 // BuildFileReader creates and instance of FileReader and opens the file that it will read from.
-func BuildFileReader[DataType constraints.Signed](fileName string) (*FileReader[DataType], error) {
+func BuildFileReader[DataType constraints.Integer](fileName string) (*FileReader[DataType], error) {
 	f, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
@@ -33,7 +36,7 @@ func (r *FileReader[DataType]) Close() error {
 }
 
 // Read reads data from file byte per byte
-func (r *FileReader[DataType]) Read(_ string) (DataType, error) {
+func (r *FileReader[DataType]) Read(_ prompt.Hint[DataType]) (DataType, error) {
 	b, err := r.in.ReadByte()
 	if err != nil {
 		return 0, err
@@ -0,0 +1,51 @@
+package reader
+
+import (
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SliceReader implements brainfuck.InputReader.
+// It reads values from a slice one by one and returns io.EOF when values are over.
+type SliceReader[DataType any] struct {
+	values []DataType
+	pos    int
+}
+
+// BuildSliceReader creates SliceReader instance that will read the values.
+func BuildSliceReader[DataType any](values ...DataType) *SliceReader[DataType] {
+	return &SliceReader[DataType]{
+		values: values,
+	}
+}
+
+// BuildStringReader creates SliceReader instance that will read the string byte per byte.
+func BuildStringReader[DataType constraints.Integer](s string) *SliceReader[DataType] {
+	values := make([]DataType, len(s))
+	for i := 0; i < len(s); i++ {
+		values[i] = DataType(s[i])
+	}
+
+	return BuildSliceReader(values...)
+}
+
+// Read returns the next value from the slice
+func (r *SliceReader[DataType]) Read(_ prompt.Hint[DataType]) (DataType, error) {
+	if r.pos >= len(r.values) {
+		var zero DataType
+		return zero, io.EOF
+	}
+
+	v := r.values[r.pos]
+	r.pos++
+
+	return v, nil
+}
+
+// Close does nothing as SliceReader doesn't hold any resources
+func (r *SliceReader[DataType]) Close() error {
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+
 	"golang.org/x/exp/constraints"
 	"golang.org/x/term"
 )
@@ -14,13 +16,13 @@ import (
 // As we want user to enter one byte only we don't wait while user will press Enter to submit input.
 // For this we change terminal state to Raw.
 // A bad consequence of it is that we need to write '\r` to it manually.
-type StdInReader[DataType constraints.Signed] struct {
+type StdInReader[DataType constraints.Integer] struct {
 	initialState *term.State
 	in           *bufio.Reader
 }
 
 // BuildStdInReader creates StdInReader instance and changes terminal state to Raw.
-func BuildStdInReader[DataType constraints.Signed]() (*StdInReader[DataType], error) {
+func BuildStdInReader[DataType constraints.Integer]() (*StdInReader[DataType], error) {
 	state, err := term.MakeRaw(0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set stdin to raw: %w", err)
@@ -47,11 +49,12 @@ func (r *StdInReader[DataType]) Close() error {
 	return nil
 }
 
-// Read reads one byte from StdIn
-func (r *StdInReader[DataType]) Read(msg string) (DataType, error) {
-	_, err := os.Stdin.Write([]byte(msg + ": "))
-	if err != nil {
-		return 0, fmt.Errorf("failed to print message: %w", err)
+// Read prints the hint text if there's any and reads one byte from StdIn
+func (r *StdInReader[DataType]) Read(hint prompt.Hint[DataType]) (DataType, error) {
+	if hint.Text != "" {
+		if _, err := os.Stdin.Write([]byte(hint.Text + ": ")); err != nil {
+			return 0, fmt.Errorf("failed to print message: %w", err)
+		}
 	}
 
 	b, err := r.in.ReadByte()
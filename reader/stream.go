@@ -0,0 +1,38 @@
+package reader
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// StreamReader implements brainfuck.InputReader.
+// It reads data from any io.Reader byte per byte.
+type StreamReader[DataType constraints.Integer] struct {
+	in *bufio.Reader
+}
+
+// BuildStreamReader creates StreamReader instance.
+func BuildStreamReader[DataType constraints.Integer](r io.Reader) *StreamReader[DataType] {
+	return &StreamReader[DataType]{
+		in: bufio.NewReader(r),
+	}
+}
+
+// Read reads one byte from the stream
+func (r *StreamReader[DataType]) Read(_ prompt.Hint[DataType]) (DataType, error) {
+	b, err := r.in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	return DataType(b), nil
+}
+
+// Close does nothing. The stream is owned by the caller.
+func (r *StreamReader[DataType]) Close() error {
+	return nil
+}
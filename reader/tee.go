@@ -0,0 +1,64 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+)
+
+// Reader mirrors brainfuck.InputReader interface.
+// It's declared here to let readers compose each other without importing the interpreter package.
+type Reader[DataType any] interface {
+	Read(prompt.Hint[DataType]) (DataType, error)
+	Close() error
+}
+
+// TeeReader implements brainfuck.InputReader.
+// It reads values from the source reader and writes every consumed value to the recorder.
+type TeeReader[DataType any] struct {
+	src      Reader[DataType]
+	recorder writer.Writer[DataType]
+}
+
+// BuildTeeReader creates TeeReader instance.
+func BuildTeeReader[DataType any](src Reader[DataType], recorder writer.Writer[DataType]) *TeeReader[DataType] {
+	return &TeeReader[DataType]{
+		src:      src,
+		recorder: recorder,
+	}
+}
+
+// Read reads a value from the source and records it.
+// Source errors are returned as is, so io.EOF is still recognised by the interpreter.
+func (r *TeeReader[DataType]) Read(hint prompt.Hint[DataType]) (DataType, error) {
+	v, err := r.src.Read(hint)
+	if err != nil {
+		return v, err
+	}
+
+	if err := r.recorder.Write(v); err != nil {
+		return v, fmt.Errorf("failed to record value: %w", err)
+	}
+
+	return v, nil
+}
+
+// Close closes both the source and the recorder. Errors are returned as writer.MultiError.
+func (r *TeeReader[DataType]) Close() error {
+	var errs writer.MultiError
+
+	if err := r.src.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close source: %w", err))
+	}
+
+	if err := r.recorder.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close recorder: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package reader
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/prompt"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns err on every call
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read(prompt.Hint[int32]) (int32, error) { return 0, r.err }
+func (r failingReader) Close() error                           { return r.err }
+
+// failingWriter returns err on every call
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(int32) error { return w.err }
+func (w failingWriter) Close() error      { return w.err }
+
+func TestTeeReader_Read(t *testing.T) {
+	t.Parallel()
+
+	recorder := writer.BuildSliceWriter[int32]()
+	r := BuildTeeReader[int32](BuildSliceReader[int32](1, 2), recorder)
+
+	for _, exp := range []int32{1, 2} {
+		v, err := r.Read(prompt.Hint[int32]{})
+		require.NoError(t, err)
+		require.Equal(t, exp, v)
+	}
+
+	// EOF is returned as is and isn't recorded
+	_, err := r.Read(prompt.Hint[int32]{})
+	require.Equal(t, io.EOF, err)
+
+	require.Equal(t, []int32{1, 2}, recorder.Values())
+	require.NoError(t, r.Close())
+}
+
+func TestTeeReader_Errors(t *testing.T) {
+	t.Parallel()
+
+	errSource := errors.New("source error")
+	errRecorder := errors.New("recorder error")
+
+	type Test struct {
+		src      Reader[int32]
+		recorder writer.Writer[int32]
+
+		expReadErrs  []error
+		expCloseErrs []error
+	}
+
+	tests := map[string]Test{
+		"source": {
+			src:          failingReader{err: errSource},
+			recorder:     writer.BuildSliceWriter[int32](),
+			expReadErrs:  []error{errSource},
+			expCloseErrs: []error{errSource},
+		},
+
+		"recorder": {
+			src:          BuildSliceReader[int32](1),
+			recorder:     failingWriter{err: errRecorder},
+			expReadErrs:  []error{errRecorder},
+			expCloseErrs: []error{errRecorder},
+		},
+
+		"source and recorder": {
+			src:      failingReader{err: errSource},
+			recorder: failingWriter{err: errRecorder},
+
+			// the recorder isn't reached when the source fails
+			expReadErrs:  []error{errSource},
+			expCloseErrs: []error{errSource, errRecorder},
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			r := BuildTeeReader(test.src, test.recorder)
+
+			_, err := r.Read(prompt.Hint[int32]{})
+			for _, expErr := range test.expReadErrs {
+				require.ErrorIs(t, err, expErr)
+			}
+
+			err = r.Close()
+
+			var multiErr writer.MultiError
+			require.ErrorAs(t, err, &multiErr)
+			require.Len(t, multiErr, len(test.expCloseErrs))
+
+			for _, expErr := range test.expCloseErrs {
+				require.ErrorIs(t, err, expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package brainfuck
+
+import (
+	"fmt"
+
+	"github.com/yurii-vyrovyi/brainfuck/sourcemap"
+)
+
+// SourceMap translates command offsets to positions in the original sources, i.e. *sourcemap.Map.
+type SourceMap interface {
+	Lookup(offset int) (sourcemap.Position, bool)
+}
+
+// CmdError is returned by Run when a command handler fails.
+type CmdError struct {
+
+	// CmdPtr is the offset of the failed command
+	CmdPtr CmdPtrType
+
+	// Cmd is the failed command
+	Cmd CmdType
+
+	// Position is the source position of the command. It's nil if there's no source map or it has no position.
+	Position *sourcemap.Position
+
+	Err error
+}
+
+func (e *CmdError) Error() string {
+	if e.Position != nil {
+		return fmt.Sprintf("failed to process [#cmd: %d, %s]: %v", e.CmdPtr, e.Position, e.Err)
+	}
+
+	return fmt.Sprintf("failed to process [#cmd: %d]: %v", e.CmdPtr, e.Err)
+}
+
+func (e *CmdError) Unwrap() error {
+	return e.Err
+}
+
+// WithSourceMap sets a source map of the code, so errors and the dump command report positions in the original sources.
+func (bf *BfInterpreter[DataType]) WithSourceMap(m SourceMap) *BfInterpreter[DataType] {
+	bf.sourceMap = m
+	return bf
+}
+
+// SourcePosition returns the source position of the current command.
+// It's false if there's no source map or the map has no position for the command.
+func (bf *BfInterpreter[DataType]) SourcePosition() (sourcemap.Position, bool) {
+	return bf.positionOf(bf.CmdPtr)
+}
+
+func (bf *BfInterpreter[DataType]) positionOf(ptr CmdPtrType) (sourcemap.Position, bool) {
+	if bf.sourceMap == nil {
+		return sourcemap.Position{}, false
+	}
+
+	return bf.sourceMap.Lookup(int(ptr))
+}
+
+// cmdError wraps an error of a command handler
+func (bf *BfInterpreter[DataType]) cmdError(ptr CmdPtrType, cmd CmdType, err error) error {
+	cmdErr := CmdError{
+		CmdPtr: ptr,
+		Cmd:    cmd,
+		Err:    err,
+	}
+
+	if pos, ok := bf.positionOf(ptr); ok {
+		cmdErr.Position = &pos
+	}
+
+	return &cmdErr
+}
@@ -0,0 +1,153 @@
+package brainfuck
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yurii-vyrovyi/brainfuck/preprocess"
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/sourcemap"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBfInterpreter_WithSourceMap(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"main.b": &fstest.MapFile{Data: []byte("#include \"io.b\"\n+*3 @read")},
+		"io.b":   &fstest.MapFile{Data: []byte("#define read >,")},
+	}
+
+	res, err := preprocess.New(files).Process("main.b")
+	require.NoError(t, err)
+
+	type Test struct {
+		sourceMap SourceMap
+
+		expPosition *sourcemap.Position
+		expErr      string
+	}
+
+	tests := map[string]Test{
+		"with source map": {
+			sourceMap:   res.Map,
+			expPosition: &sourcemap.Position{File: "io.b", Line: 1, Column: 15},
+			expErr:      "failed to process [#cmd: 4, io.b:1:15]: failed to read value: EOF",
+		},
+
+		"without source map": {
+			expErr: "failed to process [#cmd: 4]: failed to read value: EOF",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]()).
+				WithoutPrompt()
+			if test.sourceMap != nil {
+				bf.WithSourceMap(test.sourceMap)
+			}
+
+			_, err := bf.Run(bytes.NewReader(res.Code))
+			require.EqualError(t, err, test.expErr)
+			require.True(t, errors.Is(err, io.EOF))
+
+			var cmdErr *CmdError
+			require.True(t, errors.As(err, &cmdErr))
+			require.Equal(t, CmdPtrType(4), cmdErr.CmdPtr)
+			require.Equal(t, CmdType(','), cmdErr.Cmd)
+			require.Equal(t, test.expPosition, cmdErr.Position)
+		})
+	}
+}
+
+func TestEnableDumpCmd_SourceMap(t *testing.T) {
+	t.Parallel()
+
+	var dump bytes.Buffer
+
+	positions := []sourcemap.Position{
+		{File: "main.b", Line: 1, Column: 1},
+		{File: "main.b", Line: 2, Column: 5},
+	}
+
+	bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]()).
+		WithSourceMap(sourcemap.Build(positions))
+	EnableDumpCmd(bf, &dump)
+
+	_, err := bf.Run(bytes.NewReader([]byte(`+#`)))
+	require.NoError(t, err)
+
+	require.Equal(t, "#cmd: 1 (main.b:2:5), ptr: 0\n"+
+		"[0]  1 0 0\n"+
+		"     ^\n", dump.String())
+
+	pos, ok := bf.SourcePosition()
+	require.False(t, ok)
+	require.Equal(t, sourcemap.Position{}, pos)
+}
+
+func TestTrace_SourceMap(t *testing.T) {
+	t.Parallel()
+
+	positions := []sourcemap.Position{
+		{File: "main.b", Line: 1, Column: 1},
+		{File: "lib.b", Line: 3, Column: 7},
+	}
+
+	var events []TraceEvent[TestDataType]
+
+	var trace bytes.Buffer
+
+	bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), writer.BuildSliceWriter[TestDataType]()).
+		WithSourceMap(sourcemap.Build(positions)).
+		Use(
+			Trace(func(e TraceEvent[TestDataType]) { events = append(events, e) }),
+			TraceTo[TestDataType](&trace),
+		)
+
+	// the comment has no handler, so it isn't traced
+	_, err := bf.Run(bytes.NewReader([]byte(`+>a+`)))
+	require.NoError(t, err)
+
+	require.Equal(t, []TraceEvent[TestDataType]{
+		{CmdPtr: 0, Cmd: '+', Position: &positions[0], DataPtr: 0, Value: 0},
+		{CmdPtr: 1, Cmd: '>', Position: &sourcemap.Position{File: "lib.b", Line: 3, Column: 7}, DataPtr: 0, Value: 1},
+		{CmdPtr: 3, Cmd: '+', DataPtr: 1, Value: 0},
+	}, events)
+
+	require.Equal(t, "#cmd: 0 (main.b:1:1) '+', ptr: 0, cell: 0\n"+
+		"#cmd: 1 (lib.b:3:7) '>', ptr: 0, cell: 1\n"+
+		"#cmd: 3 '+', ptr: 1, cell: 0\n", trace.String())
+}
+
+func TestEnablePbrain_Preprocessed(t *testing.T) {
+	t.Parallel()
+
+	output := writer.BuildSliceWriter[TestDataType]()
+
+	bf := New[TestDataType](3, reader.BuildSliceReader[TestDataType](), output)
+	EnablePbrain(bf)
+
+	files := fstest.MapFS{
+		"main.b": &fstest.MapFile{Data: []byte("#define inc(n) >+*@n.<\n(@inc(2)) :: // calls the procedure twice")},
+	}
+
+	res, err := preprocess.WithCommands(preprocess.New(files), bf.Commands()).Process("main.b")
+	require.NoError(t, err)
+	require.Equal(t, "(>++.<)::", string(res.Code))
+
+	_, err = bf.Run(bytes.NewReader(res.Code))
+	require.NoError(t, err)
+	require.Equal(t, []TestDataType{2, 4}, output.Values())
+}
@@ -0,0 +1,204 @@
+// Package sourcemap maps offsets of commands in generated brainfuck code back to original sources.
+//
+// A map is stored as JSON:
+//
+//	{
+//	  "version": 1,
+//	  "length": 12,
+//	  "files": ["main.b", "lib.b"],
+//	  "segments": [[0, 0, 1, 1], [5, 1, 3, 17]]
+//	}
+//
+// Every segment is [offset, file index, line, column]. A segment covers commands from its offset to the offset
+// of the next segment (or to length), and these commands are written one after another in the source,
+// so a command at offset+n is at column+n.
+package sourcemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Version is the version of the format that is written by Save
+const Version = 1
+
+// Position is a location in a source file. Line and Column start with 1.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Segment is a range of commands that are written one after another in a source file
+type Segment struct {
+	Offset int
+	File   int
+	Line   int
+	Column int
+}
+
+// MarshalJSON writes segment as [offset, file, line, column]
+func (s Segment) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]int{s.Offset, s.File, s.Line, s.Column})
+}
+
+// UnmarshalJSON reads segment from [offset, file, line, column]
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	var v []int
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if len(v) != 4 {
+		return fmt.Errorf("segment must have 4 fields, got %d", len(v))
+	}
+
+	*s = Segment{Offset: v[0], File: v[1], Line: v[2], Column: v[3]}
+
+	return nil
+}
+
+// Map is a source map of generated code
+type Map struct {
+	Version  int       `json:"version"`
+	Length   int       `json:"length"`
+	Files    []string  `json:"files"`
+	Segments []Segment `json:"segments"`
+
+	// fileIdx is an index of Files, it's used while the map is built
+	fileIdx map[string]int
+}
+
+// New creates an empty Map
+func New() *Map {
+	return &Map{
+		Version: Version,
+	}
+}
+
+// Build creates Map from positions of every command
+func Build(positions []Position) *Map {
+	m := New()
+	for _, pos := range positions {
+		m.Append(pos)
+	}
+
+	return m
+}
+
+// Append adds the position of the next command
+func (m *Map) Append(pos Position) {
+	offset := m.Length
+	m.Length++
+
+	if n := len(m.Segments); n > 0 {
+		last := m.Segments[n-1]
+		if m.Files[last.File] == pos.File && last.Line == pos.Line && last.Column+offset-last.Offset == pos.Column {
+			return
+		}
+	}
+
+	if m.fileIdx == nil {
+		m.fileIdx = make(map[string]int, len(m.Files))
+		for i, f := range m.Files {
+			m.fileIdx[f] = i
+		}
+	}
+
+	file, ok := m.fileIdx[pos.File]
+	if !ok {
+		file = len(m.Files)
+		m.Files = append(m.Files, pos.File)
+		m.fileIdx[pos.File] = file
+	}
+
+	m.Segments = append(m.Segments, Segment{Offset: offset, File: file, Line: pos.Line, Column: pos.Column})
+}
+
+// Lookup returns the source position of a command by its offset in generated code
+func (m *Map) Lookup(offset int) (Position, bool) {
+	if offset < 0 || offset >= m.Length {
+		return Position{}, false
+	}
+
+	// the last segment that starts at or before offset
+	i := sort.Search(len(m.Segments), func(i int) bool { return m.Segments[i].Offset > offset }) - 1
+	if i < 0 {
+		return Position{}, false
+	}
+
+	s := m.Segments[i]
+
+	return Position{
+		File:   m.Files[s.File],
+		Line:   s.Line,
+		Column: s.Column + offset - s.Offset,
+	}, true
+}
+
+// Save writes the map as JSON
+func (m *Map) Save(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(m); err != nil {
+		return fmt.Errorf("failed to encode source map: %w", err)
+	}
+
+	return nil
+}
+
+// SaveFile writes the map to a file
+func (m *Map) SaveFile(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := m.Save(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Load reads a map from JSON
+func Load(r io.Reader) (*Map, error) {
+	var m Map
+
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode source map: %w", err)
+	}
+
+	if m.Version != Version {
+		return nil, fmt.Errorf("unsupported source map version: %d", m.Version)
+	}
+
+	for i, s := range m.Segments {
+		if i > 0 && s.Offset <= m.Segments[i-1].Offset {
+			return nil, fmt.Errorf("source map segments are not sorted [#segment: %d]", i)
+		}
+
+		if s.File < 0 || s.File >= len(m.Files) {
+			return nil, fmt.Errorf("invalid file index [#segment: %d]: %d", i, s.File)
+		}
+	}
+
+	return &m, nil
+}
+
+// LoadFile reads a map from a file
+func LoadFile(fileName string) (*Map, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
@@ -0,0 +1,87 @@
+package sourcemap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap_Lookup(t *testing.T) {
+	t.Parallel()
+
+	positions := []Position{
+		{File: "main.b", Line: 1, Column: 1},
+		{File: "main.b", Line: 1, Column: 2},
+		{File: "main.b", Line: 1, Column: 3},
+		{File: "lib.b", Line: 4, Column: 7},
+		{File: "lib.b", Line: 4, Column: 7},
+		{File: "main.b", Line: 2, Column: 1},
+	}
+
+	m := Build(positions)
+	require.Equal(t, []string{"main.b", "lib.b"}, m.Files)
+	require.Len(t, m.Segments, 4)
+
+	for offset, exp := range positions {
+		pos, ok := m.Lookup(offset)
+		require.True(t, ok)
+		require.Equal(t, exp, pos)
+	}
+
+	_, ok := m.Lookup(-1)
+	require.False(t, ok)
+
+	_, ok = m.Lookup(len(positions))
+	require.False(t, ok)
+}
+
+func TestMap_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	m := Build([]Position{
+		{File: "main.b", Line: 1, Column: 1},
+		{File: "main.b", Line: 1, Column: 2},
+		{File: "lib.b", Line: 3, Column: 17},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, m.Save(&buf))
+	require.JSONEq(t, `{"version":1,"length":3,"files":["main.b","lib.b"],"segments":[[0,0,1,1],[2,1,3,17]]}`, buf.String())
+
+	loaded, err := Load(&buf)
+	require.NoError(t, err)
+
+	pos, ok := loaded.Lookup(2)
+	require.True(t, ok)
+	require.Equal(t, Position{File: "lib.b", Line: 3, Column: 17}, pos)
+
+	// appending to a loaded map
+	loaded.Append(Position{File: "lib.b", Line: 3, Column: 18})
+	require.Len(t, loaded.Segments, 2)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"invalid json":        `{`,
+		"unsupported version": `{"version":2}`,
+		"invalid segment":     `{"version":1,"segments":[[0,0]]}`,
+		"unsorted segments":   `{"version":1,"length":3,"files":["a"],"segments":[[1,0,1,1],[0,0,2,1]]}`,
+		"invalid file index":  `{"version":1,"length":1,"files":["a"],"segments":[[0,1,1,1]]}`,
+	}
+
+	//nolint:paralleltest
+	for description, src := range tests {
+		src := src
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(strings.NewReader(src))
+			require.Error(t, err)
+		})
+	}
+}
@@ -82,3 +82,14 @@ func (s *Stack[T]) Equals(v *Stack[T], cmp func(a, b *T) bool) bool {
 
 	return true
 }
+
+// Clone returns a copy of the stack. Values are copied shallowly.
+func (s *Stack[T]) Clone() *Stack[T] {
+	c := Stack[T]{}
+
+	for e := s.l.Back(); e != nil; e = e.Prev() {
+		c.Push(*e.Value.(*T))
+	}
+
+	return &c
+}
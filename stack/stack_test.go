@@ -105,3 +105,16 @@ func TestStack_Equals(t *testing.T) {
 		})
 	}
 }
+
+func TestStack_Clone(t *testing.T) {
+	t.Parallel()
+
+	s := BuildStack[int](1, 2, 3)
+	c := s.Clone()
+
+	require.True(t, s.Equals(c, func(a, b *int) bool { return *a == *b }))
+
+	c.Push(0)
+	require.Equal(t, 3, s.Len())
+	require.Equal(t, 1, *s.Get())
+}
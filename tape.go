@@ -0,0 +1,119 @@
+package brainfuck
+
+import "sort"
+
+// Tape is an interface of interpreter memory.
+// It allows to replace a default slice memory (BfInterpreter.Data) with other implementations.
+type Tape[DataType any] interface {
+
+	// Get returns the value of the cell
+	Get(ptr DataPtrType) DataType
+
+	// Set changes the value of the cell
+	Set(ptr DataPtrType, v DataType)
+
+	// Len returns the number of cells
+	Len() DataPtrType
+}
+
+// SliceTape is a Tape that can return its whole content as a slice.
+type SliceTape[DataType any] interface {
+	Tape[DataType]
+
+	// Slice returns the content of the tape
+	Slice() []DataType
+}
+
+// ResettableTape is a Tape that may be cleared by BfInterpreter.Reset.
+type ResettableTape[DataType any] interface {
+	Tape[DataType]
+
+	// Reset sets all cells to zero
+	Reset()
+}
+
+// PointerTape is a Tape that stores the data pointer, i.e. in a file.
+// Run starts from the stored cell and stores the pointer when it stops, so a program may resume where it stopped.
+type PointerTape[DataType any] interface {
+	Tape[DataType]
+
+	// Pointer returns the stored data pointer
+	Pointer() DataPtrType
+
+	// SetPointer stores the data pointer
+	SetPointer(ptr DataPtrType)
+}
+
+// SparsePageSize is the number of cells in SparseTape page
+const SparsePageSize = 4096
+
+// SparseTape implements Tape that allocates memory in pages only when cells are changed.
+// It allows to emulate huge address spaces (i.e. 2^32 cells) for programs that touch a few cells only.
+// Cells that were never set have zero value of DataType.
+type SparseTape[DataType any] struct {
+	pages map[DataPtrType][]DataType
+	size  DataPtrType
+}
+
+// BuildSparseTape creates SparseTape instance with the given number of cells.
+func BuildSparseTape[DataType any](size DataPtrType) *SparseTape[DataType] {
+	return &SparseTape[DataType]{
+		pages: make(map[DataPtrType][]DataType),
+		size:  size,
+	}
+}
+
+// Get returns the value of the cell
+func (t *SparseTape[DataType]) Get(ptr DataPtrType) DataType {
+	page, ok := t.pages[ptr/SparsePageSize]
+	if !ok {
+		var zero DataType
+		return zero
+	}
+
+	return page[ptr%SparsePageSize]
+}
+
+// Set changes the value of the cell and allocates its page if necessary
+func (t *SparseTape[DataType]) Set(ptr DataPtrType, v DataType) {
+	page, ok := t.pages[ptr/SparsePageSize]
+	if !ok {
+		page = make([]DataType, SparsePageSize)
+		t.pages[ptr/SparsePageSize] = page
+	}
+
+	page[ptr%SparsePageSize] = v
+}
+
+// Len returns the number of cells
+func (t *SparseTape[DataType]) Len() DataPtrType {
+	return t.size
+}
+
+// Reset releases all pages
+func (t *SparseTape[DataType]) Reset() {
+	t.pages = make(map[DataPtrType][]DataType)
+}
+
+// Pages returns the number of allocated pages
+func (t *SparseTape[DataType]) Pages() int {
+	return len(t.pages)
+}
+
+// Range calls f for every cell of allocated pages in ascending order. It stops when f returns false.
+func (t *SparseTape[DataType]) Range(f func(ptr DataPtrType, v DataType) bool) {
+	idx := make([]DataPtrType, 0, len(t.pages))
+	for i := range t.pages {
+		idx = append(idx, i)
+	}
+
+	sort.Slice(idx, func(i, j int) bool { return idx[i] < idx[j] })
+
+	for _, i := range idx {
+		for j, v := range t.pages[i] {
+			if !f(i*SparsePageSize+DataPtrType(j), v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+//go:build amd64 || arm64 || ppc64 || ppc64le || mips64 || mips64le || riscv64 || s390x || loong64
+
+package brainfuck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSparseTape_4G runs a program on a tape of 2^32 cells that is allocated only where it's touched
+func TestSparseTape_4G(t *testing.T) {
+	t.Parallel()
+
+	const size = 1 << 32
+
+	tape := BuildSparseTape[TestDataType](size)
+	require.Equal(t, DataPtrType(size), tape.Len())
+
+	output := writer.BuildSliceWriter[TestDataType]()
+	bf := NewWithTape[TestDataType](tape, reader.BuildSliceReader[TestDataType](), output)
+
+	// walking to the last cell: 2^32-1 moves are too many for a test, so the pointer is set by a handler
+	bf.WithCmd('L', func(bf *BfInterpreter[TestDataType]) error {
+		bf.DataPtr = size - 2
+		return nil
+	})
+
+	_, err := bf.Run(strings.NewReader(`+L+>++.>`))
+	require.Error(t, err) // the last cell is the boundary
+
+	require.Equal(t, []TestDataType{2}, output.Values())
+	require.Equal(t, TestDataType(1), tape.Get(0))
+	require.Equal(t, TestDataType(1), tape.Get(size-2))
+	require.Equal(t, TestDataType(2), tape.Get(size-1))
+	require.Equal(t, TestDataType(0), tape.Get(1<<31))
+	require.Equal(t, 2, tape.Pages())
+}
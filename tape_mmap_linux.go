@@ -0,0 +1,136 @@
+//go:build linux
+
+package brainfuck
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/sys/unix"
+)
+
+// mmapHeaderSize is the size of MmapTape file header that stores the data pointer
+const mmapHeaderSize = 8
+
+// MmapTape implements SliceTape and PointerTape over a memory-mapped file.
+// Memory and the data pointer persist across runs, so a program may resume where it stopped
+// and the tape of a crashed run may be inspected with external tools.
+// The file starts with 8 bytes header – the data pointer as int64, cells follow it.
+// Both are stored in native byte order.
+type MmapTape[DataType constraints.Integer] struct {
+	f    *os.File
+	mem  []byte
+	ptr  *int64
+	data []DataType
+}
+
+// BuildMmapTape opens or creates the file and maps size cells of it to memory.
+// The file is extended with zeros if it's shorter than the tape.
+// It fails if the stored pointer is out of the tape, i.e. when the file is opened with a smaller size.
+func BuildMmapTape[DataType constraints.Integer](fileName string, size DataPtrType) (*MmapTape[DataType], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid tape size: %d", size)
+	}
+
+	var zero DataType
+	memSize := mmapHeaderSize + int64(size)*int64(unsafe.Sizeof(zero))
+
+	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if stat.Size() < memSize {
+		if err := f.Truncate(memSize); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to extend file: %w", err)
+		}
+	}
+
+	mem, err := unix.Mmap(int(f.Fd()), 0, int(memSize), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to map file: %w", err)
+	}
+
+	// mapped memory is page aligned, so are the header and cells
+	t := MmapTape[DataType]{
+		f:    f,
+		mem:  mem,
+		ptr:  (*int64)(unsafe.Pointer(&mem[0])),
+		data: unsafe.Slice((*DataType)(unsafe.Pointer(&mem[mmapHeaderSize])), size),
+	}
+
+	if *t.ptr < 0 || *t.ptr >= int64(size) {
+		ptr := *t.ptr
+		_ = unix.Munmap(mem)
+		_ = f.Close()
+		return nil, fmt.Errorf("stored pointer %d is out of tape", ptr)
+	}
+
+	return &t, nil
+}
+
+// Get returns the value of the cell
+func (t *MmapTape[DataType]) Get(ptr DataPtrType) DataType {
+	return t.data[ptr]
+}
+
+// Set changes the value of the cell
+func (t *MmapTape[DataType]) Set(ptr DataPtrType, v DataType) {
+	t.data[ptr] = v
+}
+
+// Len returns the number of cells
+func (t *MmapTape[DataType]) Len() DataPtrType {
+	return DataPtrType(len(t.data))
+}
+
+// Pointer returns the stored data pointer
+func (t *MmapTape[DataType]) Pointer() DataPtrType {
+	return DataPtrType(*t.ptr)
+}
+
+// SetPointer stores the data pointer
+func (t *MmapTape[DataType]) SetPointer(ptr DataPtrType) {
+	*t.ptr = int64(ptr)
+}
+
+// Slice returns the mapped memory. It must not be used after Close.
+func (t *MmapTape[DataType]) Slice() []DataType {
+	return t.data
+}
+
+// Sync flushes changes to the file
+func (t *MmapTape[DataType]) Sync() error {
+	if err := unix.Msync(t.mem, unix.MS_SYNC); err != nil {
+		return fmt.Errorf("failed to sync memory: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes changes, unmaps memory and closes the file
+func (t *MmapTape[DataType]) Close() error {
+	if err := t.Sync(); err != nil {
+		return err
+	}
+
+	if err := unix.Munmap(t.mem); err != nil {
+		return fmt.Errorf("failed to unmap memory: %w", err)
+	}
+
+	t.mem = nil
+	t.ptr = nil
+	t.data = nil
+
+	return t.f.Close()
+}
@@ -0,0 +1,112 @@
+//go:build linux
+
+package brainfuck
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMmapTape(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "tape.bin")
+
+	run := func(commands string) []int16 {
+		tape, err := BuildMmapTape[int16](fileName, 4)
+		require.NoError(t, err)
+
+		bf := New[int16](0, reader.BuildSliceReader[int16](), writer.BuildSliceWriter[int16]()).
+			WithTape(tape)
+
+		resData, err := bf.Run(strings.NewReader(commands))
+		require.NoError(t, err)
+
+		res := append([]int16(nil), resData...)
+		require.NoError(t, tape.Close())
+
+		return res
+	}
+
+	require.Equal(t, []int16{3, 0, 0, 0}, run(`+++`))
+
+	// the second run continues with the memory of the first one
+	require.Equal(t, []int16{4, -1, 0, 0}, run(`+>-`))
+
+	// and the third one continues from the cell where the second one stopped
+	require.Equal(t, []int16{4, -2, 1, 0}, run(`->+`))
+
+	// the stored pointer doesn't fit into a smaller tape
+	_, err := BuildMmapTape[int16](fileName, 2)
+	require.Error(t, err)
+
+	tape, err := BuildMmapTape[int16](fileName, 4)
+	require.NoError(t, err)
+	require.Equal(t, DataPtrType(2), tape.Pointer())
+
+	// Reset starts from the first cell again
+	bf := New[int16](0, reader.BuildSliceReader[int16](), writer.BuildSliceWriter[int16]()).
+		WithTape(tape)
+	bf.Reset()
+
+	resData, err := bf.Run(strings.NewReader(`+`))
+	require.NoError(t, err)
+	require.Equal(t, []int16{5, -2, 1, 0}, resData)
+	require.NoError(t, tape.Close())
+}
+
+func TestMmapTape_FailedRun(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "tape.bin")
+
+	tape, err := BuildMmapTape[uint8](fileName, 4)
+	require.NoError(t, err)
+
+	bf := New[uint8](0, reader.BuildSliceReader[uint8](), writer.BuildSliceWriter[uint8]()).
+		WithTape(tape)
+
+	// the pointer of a crashed run is stored too
+	_, err = bf.Run(strings.NewReader(`>>+,`))
+	require.Error(t, err)
+	require.NoError(t, tape.Close())
+
+	tape, err = BuildMmapTape[uint8](fileName, 4)
+	require.NoError(t, err)
+	require.Equal(t, DataPtrType(2), tape.Pointer())
+	require.Equal(t, []uint8{0, 0, 1, 0}, tape.Slice())
+	require.NoError(t, tape.Close())
+}
+
+func TestMmapTape_MultiTape(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "tape.bin")
+
+	tape, err := BuildMmapTape[uint8](fileName, 4)
+	require.NoError(t, err)
+
+	bf := New[uint8](0, reader.BuildSliceReader[uint8](), writer.BuildSliceWriter[uint8]()).
+		WithTape(tape)
+	EnableMultiTape(bf, 2)
+
+	// the run ends on the secondary tape, but the main tape is returned and keeps its own pointer
+	resData, err := bf.Run(strings.NewReader(`>>+^>>>+`))
+	require.NoError(t, err)
+	require.Equal(t, []uint8{0, 0, 1, 0}, resData)
+	require.Equal(t, DataPtrType(2), tape.Pointer())
+
+	// the same is true for a failed run
+	_, err = bf.Run(strings.NewReader(`<+^>>>>`))
+	require.Error(t, err)
+	require.Equal(t, []uint8{0, 1, 1, 0}, tape.Slice())
+	require.Equal(t, DataPtrType(1), tape.Pointer())
+
+	require.NoError(t, tape.Close())
+}
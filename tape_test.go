@@ -0,0 +1,45 @@
+package brainfuck
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/yurii-vyrovyi/brainfuck/reader"
+	"github.com/yurii-vyrovyi/brainfuck/writer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparseTape(t *testing.T) {
+	t.Parallel()
+
+	// the largest tape that fits in int on any platform
+	tape := BuildSparseTape[TestDataType](math.MaxInt)
+
+	output := writer.BuildSliceWriter[TestDataType]()
+	bf := NewWithTape[TestDataType](tape, reader.BuildSliceReader[TestDataType](), output)
+	require.Nil(t, bf.Data)
+
+	resData, err := bf.Run(strings.NewReader(`+>++.<<`))
+	require.Error(t, err) // shifting left from the first cell is still out of boundary
+	require.Nil(t, resData)
+
+	require.Equal(t, []TestDataType{2}, output.Values())
+	require.Equal(t, TestDataType(1), tape.Get(0))
+	require.Equal(t, TestDataType(2), tape.Get(1))
+
+	tape.Set(math.MaxInt-1, 7)
+	require.Equal(t, TestDataType(7), tape.Get(math.MaxInt-1))
+	require.Equal(t, TestDataType(0), tape.Get(math.MaxInt/2))
+	require.Equal(t, 2, tape.Pages())
+
+	var touched []DataPtrType
+	tape.Range(func(ptr DataPtrType, v TestDataType) bool {
+		if v != 0 {
+			touched = append(touched, ptr)
+		}
+		return true
+	})
+	require.Equal(t, []DataPtrType{0, 1, math.MaxInt - 1}, touched)
+}
@@ -0,0 +1,193 @@
+// Package tapeview renders interpreter memory in human and machine readable formats.
+// Every renderer gets the tape as a slice, i.e. a result of BfInterpreter.Run, and writes to io.Writer.
+package tapeview
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Format is a tape rendering format
+type Format string
+
+const (
+	FormatHex    Format = "hex"
+	FormatWindow Format = "window"
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+)
+
+const (
+	// hexRowSize is the number of cells in a hexdump row
+	hexRowSize = 16
+
+	// DefaultRadius is the number of cells that Window shows on each side of the pointer
+	DefaultRadius = 8
+)
+
+// ParseFormat converts a format name (i.e. a command line flag) to Format.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatHex, FormatWindow, FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown tape format: %s", s)
+	}
+}
+
+// Render writes the tape in the given format. ptr is used by FormatWindow only.
+// Trailing zeros are trimmed for all formats except FormatWindow.
+func Render[DataType constraints.Integer](w io.Writer, format Format, data []DataType, ptr int) error {
+	switch format {
+	case FormatHex:
+		return Hexdump(w, TrimZeros(data))
+	case FormatWindow:
+		return Window(w, data, ptr, DefaultRadius)
+	case FormatJSON:
+		return JSON(w, TrimZeros(data))
+	case FormatCSV:
+		return CSV(w, TrimZeros(data))
+	default:
+		return fmt.Errorf("unknown tape format: %s", format)
+	}
+}
+
+// TrimZeros returns the tape without trailing zero cells.
+func TrimZeros[DataType constraints.Integer](data []DataType) []DataType {
+	n := len(data)
+	for n > 0 && data[n-1] == 0 {
+		n--
+	}
+
+	return data[:n]
+}
+
+// Hexdump writes the tape as a hexdump: an offset, cells in hex and their printable characters.
+// Cells are shown in two's complement with as many digits as the cell type size requires.
+func Hexdump[DataType constraints.Integer](w io.Writer, data []DataType) error {
+	var zero DataType
+	cellSize := int(unsafe.Sizeof(zero))
+	mask := uint64(1)<<(8*cellSize) - 1
+	if cellSize == 8 {
+		mask = ^uint64(0)
+	}
+
+	for row := 0; row < len(data); row += hexRowSize {
+		end := row + hexRowSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		var sb strings.Builder
+
+		fmt.Fprintf(&sb, "%08x ", row)
+
+		for i := row; i < row+hexRowSize; i++ {
+			if i < end {
+				fmt.Fprintf(&sb, " %0*x", cellSize*2, uint64(data[i])&mask)
+			} else {
+				sb.WriteString(strings.Repeat(" ", cellSize*2+1))
+			}
+		}
+
+		sb.WriteString("  |")
+		for i := row; i < end; i++ {
+			if data[i] >= 0x20 && data[i] < 0x7f {
+				sb.WriteByte(byte(data[i]))
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteString("|\n")
+
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Window writes radius cells around ptr in one line and a caret marker under the current cell in the second one.
+func Window[DataType constraints.Integer](w io.Writer, data []DataType, ptr int, radius int) error {
+	// ptr may be out of data, i.e. when a tape is trimmed, so both bounds are clamped to data
+	from := clamp(ptr-radius, 0, len(data))
+	to := clamp(ptr+radius+1, from, len(data))
+
+	return Segment(w, data[from:to], from, ptr)
+}
+
+// clamp returns v limited to [lo, hi]
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+// Segment writes a part of the tape like Window does. cells is the part of the tape that starts at offset.
+// It allows to render tapes that can't be represented as a slice, i.e. sparse ones.
+func Segment[DataType constraints.Integer](w io.Writer, cells []DataType, offset int, ptr int) error {
+	var values, marker strings.Builder
+
+	prefix := fmt.Sprintf("[%d] ", offset)
+	values.WriteString(prefix)
+	marker.WriteString(strings.Repeat(" ", len(prefix)))
+
+	for i, cell := range cells {
+		v := fmt.Sprint(cell)
+
+		values.WriteString(" " + v)
+
+		if offset+i == ptr {
+			marker.WriteString(" " + strings.Repeat("^", len(v)))
+		} else {
+			marker.WriteString(strings.Repeat(" ", len(v)+1))
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n%s\n", values.String(), strings.TrimRight(marker.String(), " "))
+	return err
+}
+
+// JSON writes the tape as a JSON array of numbers.
+// Values are converted to json.Number explicitly, as encoding/json writes []uint8 as a base64 string.
+func JSON[DataType constraints.Integer](w io.Writer, data []DataType) error {
+	values := make([]json.Number, len(data))
+	for i, v := range data {
+		values[i] = json.Number(fmt.Sprint(v))
+	}
+
+	return json.NewEncoder(w).Encode(values)
+}
+
+// CSV writes the tape as "index,value" rows with a header.
+func CSV[DataType constraints.Integer](w io.Writer, data []DataType) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"index", "value"}); err != nil {
+		return err
+	}
+
+	for i, v := range data {
+		if err := cw.Write([]string{strconv.Itoa(i), fmt.Sprint(v)}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,104 @@
+package tapeview
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		format Format
+		data   []uint8
+		ptr    int
+
+		expOutput string
+	}
+
+	tests := map[string]Test{
+		"Hex": {
+			format:    FormatHex,
+			data:      []uint8{'H', 'i', 0, 255, 0, 0},
+			expOutput: "00000000  48 69 00 ff" + "                                    " + "  |Hi..|\n",
+		},
+
+		"Window": {
+			format: FormatWindow,
+			data:   []uint8{1, 0, 12, 255, 0},
+			ptr:    3,
+			expOutput: "[0]  1 0 12 255 0\n" +
+				"            ^^^\n",
+		},
+
+		"Window with pointer after data": {
+			format:    FormatWindow,
+			data:      []uint8{1, 2, 3},
+			ptr:       20,
+			expOutput: "[3] \n\n",
+		},
+
+		"Window with pointer before data": {
+			format:    FormatWindow,
+			data:      []uint8{1, 2, 3},
+			ptr:       -20,
+			expOutput: "[0] \n\n",
+		},
+
+		"JSON": {
+			format:    FormatJSON,
+			data:      []uint8{1, 0, 200, 0, 0},
+			expOutput: "[1,0,200]\n",
+		},
+
+		"CSV": {
+			format:    FormatCSV,
+			data:      []uint8{1, 0, 200, 0},
+			expOutput: "index,value\n0,1\n1,0\n2,200\n",
+		},
+
+		"Empty JSON": {
+			format:    FormatJSON,
+			data:      []uint8{0, 0},
+			expOutput: "[]\n",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := Render(&buf, test.format, test.data, test.ptr)
+			require.NoError(t, err)
+			require.Equal(t, test.expOutput, buf.String())
+		})
+	}
+}
+
+func TestHexdump_Signed(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := Hexdump(&buf, []int16{-1, 65})
+	require.NoError(t, err)
+	require.Equal(t, "00000000  ffff 0041"+"                                                                      "+"  |.A|\n", buf.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFormat("JSON")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSON, f)
+
+	_, err = ParseFormat("xml")
+	require.Error(t, err)
+}
@@ -1,15 +1,20 @@
 package brainfuck
 
+import "github.com/yurii-vyrovyi/brainfuck/prompt"
+
 //go:generate mockgen -source test-interfaces.go -destination mock_brainfuck.go -package brainfuck
 
 // These interfaces are necessary to generate InputReader and OutputWrite mocks.
 // While original interfaces InputReader and OutputWrite use generics TestXXX ones use TestDataType as a data type.
 // This type is used in all tests.
+// TestHint is an alias as mockgen can't parse generic types instantiation in method signatures.
 type (
 	TestDataType int32
 
+	TestHint = prompt.Hint[TestDataType]
+
 	TestInputReader interface {
-		Read(string) (TestDataType, error)
+		Read(TestHint) (TestDataType, error)
 		Close() error
 	}
 
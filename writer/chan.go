@@ -0,0 +1,44 @@
+package writer
+
+import (
+	"context"
+	"sync"
+)
+
+// ChanWriter implements brainfuck.OutputWriter interface.
+// It sends output values to a channel, so they may be consumed by another interpreter with reader.ChanReader.
+// Write blocks until the value is received or the context is done.
+type ChanWriter[DataType any] struct {
+	ctx  context.Context
+	ch   chan<- DataType
+	once sync.Once
+}
+
+// BuildChanWriter creates ChanWriter instance that writes to ch until ctx is done.
+func BuildChanWriter[DataType any](ctx context.Context, ch chan<- DataType) *ChanWriter[DataType] {
+	return &ChanWriter[DataType]{
+		ctx: ctx,
+		ch:  ch,
+	}
+}
+
+// Write sends a value to the channel
+func (w *ChanWriter[DataType]) Write(v DataType) error {
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+
+	case w.ch <- v:
+		return nil
+	}
+}
+
+// Close closes the channel, that signals the end of data to the reading side.
+// It's safe to call Close several times.
+func (w *ChanWriter[DataType]) Close() error {
+	w.once.Do(func() {
+		close(w.ch)
+	})
+
+	return nil
+}
@@ -3,20 +3,84 @@ package writer
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+)
+
+// FileMode defines how FileWriter opens the output file.
+type FileMode int
+
+const (
+	// ModeTruncate creates the file or truncates an existing one. It's the default mode.
+	ModeTruncate FileMode = iota
+
+	// ModeAppend creates the file or appends output to the end of an existing one.
+	ModeAppend
+
+	// ModeExclusive creates a new file and fails if the file already exists.
+	ModeExclusive
 
-	"golang.org/x/exp/constraints"
+	// ModeAtomic writes output to a temporary file in the same directory
+	// and renames it to the target name on Close.
+	// Close always commits the output, as the writer doesn't know whether the run succeeded,
+	// so callers must call Discard when the run fails to leave the target file untouched:
+	//
+	//	w, err := writer.BuildFileWriterWithMode[uint8]("out.txt", writer.ModeAtomic)
+	//	...
+	//	defer w.Close() // does nothing after Discard
+	//
+	//	if _, err := bf.Run(commands); err != nil {
+	//		_ = w.Discard()
+	//		return err
+	//	}
+	ModeAtomic
 )
 
 // FileWriter implements brainfuck.OutputWriter interface.
 // File writer stores output to a file.
-type FileWriter[DataType constraints.Signed] struct {
+type FileWriter[DataType any] struct {
 	f *os.File
+
+	// target is the file name that temporary file is renamed to in ModeAtomic
+	target string
+
+	// discarded is set by Discard, so a deferred Close doesn't commit the output
+	discarded bool
 }
 
-// BuildFileWriter creates FileWriter instance and creates/opens a file that data will be written to.
-func BuildFileWriter[DataType constraints.Signed](fileName string) (*FileWriter[DataType], error) {
+// BuildFileWriter creates FileWriter instance and creates/truncates a file that data will be written to.
+func BuildFileWriter[DataType any](fileName string) (*FileWriter[DataType], error) {
+	return BuildFileWriterWithMode[DataType](fileName, ModeTruncate)
+}
+
+// BuildFileWriterWithMode creates FileWriter instance and opens a file according to mode.
+func BuildFileWriterWithMode[DataType any](fileName string, mode FileMode) (*FileWriter[DataType], error) {
+
+	if mode == ModeAtomic {
+		f, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		}
 
-	f, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0644)
+		return &FileWriter[DataType]{
+			f:      f,
+			target: fileName,
+		}, nil
+	}
+
+	var flag int
+
+	switch mode {
+	case ModeTruncate:
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	case ModeAppend:
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	case ModeExclusive:
+		flag = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	default:
+		return nil, fmt.Errorf("unknown file mode: %d", mode)
+	}
+
+	f, err := os.OpenFile(fileName, flag, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
@@ -28,7 +92,7 @@ func BuildFileWriter[DataType constraints.Signed](fileName string) (*FileWriter[
 
 // Write writes value to the file
 func (w *FileWriter[DataType]) Write(v DataType) error {
-	if _, err := w.f.Write([]byte(fmt.Sprintf("%d ", v))); err != nil {
+	if _, err := w.f.Write([]byte(fmt.Sprintf("%v ", v))); err != nil {
 		return err
 	}
 
@@ -36,6 +100,56 @@ func (w *FileWriter[DataType]) Write(v DataType) error {
 }
 
 // Close closes the underlying file.
+// In ModeAtomic it also moves the temporary file to the target file name unless the output is discarded.
 func (w *FileWriter[DataType]) Close() error {
-	return w.f.Close()
+	if w.target == "" {
+		return w.f.Close()
+	}
+
+	if w.discarded {
+		return nil
+	}
+
+	if err := w.f.Sync(); err != nil {
+		_ = w.Discard()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	if err := w.f.Close(); err != nil {
+		_ = os.Remove(w.f.Name())
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Chmod(w.f.Name(), 0644); err != nil {
+		_ = os.Remove(w.f.Name())
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	if err := os.Rename(w.f.Name(), w.target); err != nil {
+		_ = os.Remove(w.f.Name())
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+// Discard closes the underlying file and removes the temporary file in ModeAtomic, leaving the target untouched.
+// In other modes it's equivalent to Close.
+func (w *FileWriter[DataType]) Discard() error {
+	if w.target == "" {
+		return w.f.Close()
+	}
+
+	if w.discarded {
+		return nil
+	}
+
+	w.discarded = true
+	closeErr := w.f.Close()
+
+	if err := os.Remove(w.f.Name()); err != nil {
+		return fmt.Errorf("failed to remove temporary file: %w", err)
+	}
+
+	return closeErr
 }
@@ -0,0 +1,90 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWriter_Modes(t *testing.T) {
+	t.Parallel()
+
+	type Test struct {
+		mode    FileMode
+		discard bool
+
+		expErr     bool
+		expContent string
+	}
+
+	tests := map[string]Test{
+		"Truncate": {
+			mode:       ModeTruncate,
+			expContent: "1 2 ",
+		},
+
+		"Append": {
+			mode:       ModeAppend,
+			expContent: "previous content1 2 ",
+		},
+
+		"Exclusive": {
+			mode:   ModeExclusive,
+			expErr: true,
+		},
+
+		"Atomic": {
+			mode:       ModeAtomic,
+			expContent: "1 2 ",
+		},
+
+		"Atomic discarded": {
+			mode:       ModeAtomic,
+			discard:    true,
+			expContent: "previous content",
+		},
+	}
+
+	//nolint:paralleltest
+	for description, test := range tests {
+		test := test
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			fileName := filepath.Join(dir, "out.txt")
+
+			require.NoError(t, os.WriteFile(fileName, []byte("previous content"), 0644))
+
+			w, err := BuildFileWriterWithMode[int32](fileName, test.mode)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, w.Write(1))
+			require.NoError(t, w.Write(2))
+
+			if test.discard {
+				require.NoError(t, w.Discard())
+
+				// a deferred Close doesn't commit the discarded output
+				require.NoError(t, w.Close())
+			} else {
+				require.NoError(t, w.Close())
+			}
+
+			content, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			require.Equal(t, test.expContent, string(content))
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+		})
+	}
+}
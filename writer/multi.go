@@ -0,0 +1,120 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Writer mirrors brainfuck.OutputWriter interface.
+// It's declared here to let writers compose each other without importing the interpreter package.
+type Writer[DataType any] interface {
+	Write(DataType) error
+	Close() error
+}
+
+// SinkError reports which of MultiWriter sinks has failed.
+type SinkError struct {
+	// Sink is the index of the failed writer as it was passed to BuildMultiWriter
+	Sink int
+
+	// Writer is a type name of the failed writer
+	Writer string
+
+	Err error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink #%d (%s): %v", e.Sink, e.Writer, e.Err)
+}
+
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates errors of several sinks.
+type MultiError []error
+
+func (e MultiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.Is and errors.As inspect every error since Go 1.20.
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// Is reports whether any of the errors matches target. It makes errors.Is work with older Go versions.
+func (e MultiError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error that matches target. It makes errors.As work with older Go versions.
+func (e MultiError) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MultiWriter implements brainfuck.OutputWriter interface.
+// It fans out every value to several writers, i.e. to a terminal and to a log file.
+// A failure of one writer doesn't stop writing to the others.
+type MultiWriter[DataType any] struct {
+	writers []Writer[DataType]
+}
+
+// BuildMultiWriter creates MultiWriter instance that writes to all writers in the given order.
+func BuildMultiWriter[DataType any](writers ...Writer[DataType]) *MultiWriter[DataType] {
+	return &MultiWriter[DataType]{
+		writers: writers,
+	}
+}
+
+// Write writes a value to every writer. Errors are returned as MultiError of SinkError.
+func (w *MultiWriter[DataType]) Write(v DataType) error {
+	return w.each(func(sink Writer[DataType]) error {
+		return sink.Write(v)
+	})
+}
+
+// Close closes every writer. Errors are returned as MultiError of SinkError.
+func (w *MultiWriter[DataType]) Close() error {
+	return w.each(func(sink Writer[DataType]) error {
+		return sink.Close()
+	})
+}
+
+func (w *MultiWriter[DataType]) each(f func(sink Writer[DataType]) error) error {
+	var errs MultiError
+
+	for i, sink := range w.writers {
+		if err := f(sink); err != nil {
+			errs = append(errs, &SinkError{
+				Sink:   i,
+				Writer: fmt.Sprintf("%T", sink),
+				Err:    err,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
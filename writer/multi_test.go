@@ -0,0 +1,61 @@
+package writer
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(int32) error { return w.err }
+func (w failingWriter) Close() error      { return w.err }
+
+func TestMultiWriter(t *testing.T) {
+	t.Parallel()
+
+	errSink := errors.New("sink error")
+
+	first := BuildSliceWriter[int32]()
+	last := BuildSliceWriter[int32]()
+
+	w := BuildMultiWriter[int32](first, failingWriter{err: errSink}, last)
+
+	err := w.Write(7)
+	require.ErrorIs(t, err, errSink)
+
+	var sinkErr *SinkError
+	require.ErrorAs(t, err, &sinkErr)
+	require.Equal(t, 1, sinkErr.Sink)
+
+	// the failed sink doesn't prevent writing to the rest of them
+	require.Equal(t, []int32{7}, first.Values())
+	require.Equal(t, []int32{7}, last.Values())
+
+	require.ErrorIs(t, w.Close(), errSink)
+}
+
+func TestMultiError(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	err := MultiError{errFirst, &SinkError{Sink: 1, Err: errSecond}}
+
+	// Is and As are called directly, as errors.Is and errors.As use Unwrap() []error since Go 1.20
+	require.True(t, err.Is(errFirst))
+	require.True(t, err.Is(errSecond))
+	require.False(t, err.Is(errors.New("first")))
+
+	var sinkErr *SinkError
+	require.True(t, err.As(&sinkErr))
+	require.Equal(t, 1, sinkErr.Sink)
+
+	var pathErr *os.PathError
+	require.False(t, err.As(&pathErr))
+}
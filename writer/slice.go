@@ -0,0 +1,42 @@
+package writer
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// SliceWriter implements brainfuck.OutputWriter interface.
+// It collects output values in memory.
+type SliceWriter[DataType constraints.Integer] struct {
+	values []DataType
+}
+
+// BuildSliceWriter creates SliceWriter instance.
+func BuildSliceWriter[DataType constraints.Integer]() *SliceWriter[DataType] {
+	return &SliceWriter[DataType]{}
+}
+
+// Write appends a value to the collected output
+func (w *SliceWriter[DataType]) Write(v DataType) error {
+	w.values = append(w.values, v)
+	return nil
+}
+
+// Close does nothing as SliceWriter doesn't hold any resources
+func (w *SliceWriter[DataType]) Close() error {
+	return nil
+}
+
+// Values returns the collected output
+func (w *SliceWriter[DataType]) Values() []DataType {
+	return w.values
+}
+
+// String returns the collected output as a string where every value is treated as a byte
+func (w *SliceWriter[DataType]) String() string {
+	b := make([]byte, len(w.values))
+	for i, v := range w.values {
+		b[i] = byte(v)
+	}
+
+	return string(b)
+}